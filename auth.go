@@ -0,0 +1,208 @@
+package oteleport
+
+import (
+	"context"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mashiike/go-otlp-helper/otlp"
+	"github.com/samber/oops"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// scopes of access keys.
+// a scope also grants the scopes under it, like `api:read` grants `api:read:traces`.
+const (
+	ScopeOTLPWrite        = "otlp:write"
+	ScopeOTLPWriteTraces  = "otlp:write:traces"
+	ScopeOTLPWriteMetrics = "otlp:write:metrics"
+	ScopeOTLPWriteLogs    = "otlp:write:logs"
+	ScopeAPIRead          = "api:read"
+	ScopeAPIReadTraces    = "api:read:traces"
+	ScopeAPIReadMetrics   = "api:read:metrics"
+	ScopeAPIReadLogs      = "api:read:logs"
+)
+
+var knownScopes = []string{
+	ScopeOTLPWrite,
+	ScopeOTLPWriteTraces,
+	ScopeOTLPWriteMetrics,
+	ScopeOTLPWriteLogs,
+	ScopeAPIRead,
+	ScopeAPIReadTraces,
+	ScopeAPIReadMetrics,
+	ScopeAPIReadLogs,
+}
+
+// HasScope reports whether the access key is granted the scope.
+// access keys without scopes are granted all scopes, for compatibility.
+func (c *AccessKeyConfig) HasScope(scope string) bool {
+	if len(c.Scopes) == 0 {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope || strings.HasPrefix(scope, s+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// secretKeyHashScheme is the scheme of `secret_key_hash`, the format is `sha256:<salt>:<digest>` in hex.
+// secret keys are random strings, so a salted SHA-256 is enough, and it is cheap to check on every request.
+const secretKeyHashScheme = "sha256"
+
+// HashSecretKey returns the salted hash of the secret key, for `secret_key_hash` of access keys.
+func HashSecretKey(secretKey string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := crand.Read(salt); err != nil {
+		return "", oops.Wrapf(err, "failed to generate salt")
+	}
+	return fmt.Sprintf("%s:%s:%s", secretKeyHashScheme, hex.EncodeToString(salt), hex.EncodeToString(secretKeyDigest(salt, secretKey))), nil
+}
+
+// GenerateSecretKey returns a new random secret key.
+func GenerateSecretKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := crand.Read(b); err != nil {
+		return "", oops.Wrapf(err, "failed to generate secret key")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func secretKeyDigest(salt []byte, secretKey string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secretKey))
+	return h.Sum(nil)
+}
+
+func parseSecretKeyHash(hash string) ([]byte, []byte, error) {
+	parts := strings.Split(hash, ":")
+	if len(parts) != 3 || parts[0] != secretKeyHashScheme {
+		return nil, nil, oops.Errorf("secret key hash must be %s:<salt>:<digest>", secretKeyHashScheme)
+	}
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, oops.Wrapf(err, "invalid salt of secret key hash")
+	}
+	digest, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, oops.Wrapf(err, "invalid digest of secret key hash")
+	}
+	if len(digest) != sha256.Size {
+		return nil, nil, oops.Errorf("invalid digest length of secret key hash")
+	}
+	return salt, digest, nil
+}
+
+// matchSecretKey reports whether the access key is the secret key of the config, in constant time.
+func (c *AccessKeyConfig) matchSecretKey(accessKey string) bool {
+	if c.SecretKey == "" && c.SecretKeyHash == "" {
+		// access keys only of client certificates.
+		return false
+	}
+	if c.SecretKeyHash == "" {
+		return subtle.ConstantTimeCompare([]byte(c.SecretKey), []byte(accessKey)) == 1
+	}
+	salt, digest, err := parseSecretKeyHash(c.SecretKeyHash)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(secretKeyDigest(salt, accessKey), digest) == 1
+}
+
+// authorize authenticates the bearer token of the authorization header when auth.jwt is enabled, the access key,
+// or the verified client certificate in ctx, and checks the scope.
+// the returned error is a gRPC status error.
+func (s *Server) authorize(ctx context.Context, accessKey string, authorization string, scope string) (*AccessKeyConfig, error) {
+	var key *AccessKeyConfig
+	token, isBearer := strings.CutPrefix(authorization, "Bearer ")
+	switch {
+	case s.jwtVerifier != nil && isBearer && token != "":
+		var err error
+		key, err = s.jwtVerifier.verify(ctx, token)
+		if err != nil {
+			slog.InfoContext(ctx, "access denided", "reason", "invalid bearer token", "error", err.Error())
+			return nil, status.Error(codes.PermissionDenied, "access denied")
+		}
+	case accessKey != "":
+		var ok bool
+		key, ok = s.lookupAccessKey(accessKey)
+		if !ok {
+			slog.InfoContext(ctx, "access denided", "reason", "access key mismatch")
+			return nil, status.Error(codes.PermissionDenied, "access denied")
+		}
+	case clientCertificateFromContext(ctx) != nil:
+		cert := clientCertificateFromContext(ctx)
+		var ok bool
+		key, ok = s.lookupClientCertificate(cert)
+		if !ok {
+			slog.InfoContext(ctx, "access denided", "reason", "client certificate mismatch", "subject", cert.Subject.String())
+			return nil, status.Error(codes.PermissionDenied, "access denied")
+		}
+	default:
+		slog.InfoContext(ctx, "access denided", "reason", "no access key found")
+		return nil, status.Error(codes.Unauthenticated, "no access key found")
+	}
+	if !key.HasScope(scope) {
+		slog.InfoContext(ctx, "access denided", "reason", "insufficient scope", "key_id", key.KeyID, "scope", scope)
+		return nil, status.Errorf(codes.PermissionDenied, "access denied: %s scope is required", scope)
+	}
+	slog.InfoContext(ctx, "authenticated", "key_id", key.KeyID, "scope", scope)
+	return key, nil
+}
+
+// otlpRequiredScope returns the scope required to export the OTLP request.
+func otlpRequiredScope(req proto.Message) string {
+	switch req.(type) {
+	case *otlp.TraceRequest:
+		return ScopeOTLPWriteTraces
+	case *otlp.MetricsRequest:
+		return ScopeOTLPWriteMetrics
+	case *otlp.LogsRequest:
+		return ScopeOTLPWriteLogs
+	}
+	return ScopeOTLPWrite
+}
+
+// apiRequiredScope returns the scope required to call the API HTTP route.
+func apiRequiredScope(r *http.Request) string {
+	var tmpl string
+	if route := mux.CurrentRoute(r); route != nil {
+		tmpl, _ = route.GetPathTemplate()
+	}
+	switch {
+	case strings.HasSuffix(tmpl, fetchTracesPath), strings.HasSuffix(tmpl, getTracePath):
+		return ScopeAPIReadTraces
+	case strings.HasSuffix(tmpl, fetchMetricsPath):
+		return ScopeAPIReadMetrics
+	case strings.HasSuffix(tmpl, fetchLogsPath):
+		return ScopeAPIReadLogs
+	}
+	return ScopeAPIRead
+}
+
+// apiGRPCRequiredScope returns the scope required to call the API gRPC method.
+func apiGRPCRequiredScope(fullMethod string) string {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	switch method {
+	case "FetchTracesData", "GetTrace":
+		return ScopeAPIReadTraces
+	case "FetchMetricsData":
+		return ScopeAPIReadMetrics
+	case "FetchLogsData":
+		return ScopeAPIReadLogs
+	}
+	return ScopeAPIRead
+}
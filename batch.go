@@ -0,0 +1,162 @@
+package oteleport
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// signalBatch is the buffered signals of a partition.
+type signalBatch[T proto.Message] struct {
+	partition string
+	data      []T
+	bytes     int
+	records   int
+	createdAt time.Time
+}
+
+// bufferBudget is the max total size of buffered batches, shared by buffers of all signals and tenants.
+type bufferBudget struct {
+	maxBytes int64
+	used     atomic.Int64
+}
+
+func newBufferBudget(maxBytes int) *bufferBudget {
+	return &bufferBudget{maxBytes: int64(maxBytes)}
+}
+
+// reserve charges bytes to the budget, and reports false without charging when the budget has no room.
+func (b *bufferBudget) reserve(bytes int) bool {
+	for {
+		used := b.used.Load()
+		if used+int64(bytes) > b.maxBytes {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+int64(bytes)) {
+			return true
+		}
+	}
+}
+
+func (b *bufferBudget) release(bytes int) {
+	b.used.Add(-int64(bytes))
+}
+
+// batchBuffer accumulates signals per partition.
+// a batch is taken out when it reaches max bytes or max records, or gets older than max age.
+// the total size of buffered batches is capped by the budget, so that the buffer does not grow while the storage is down.
+type batchBuffer[T proto.Message] struct {
+	mu         sync.Mutex
+	maxBytes   int
+	maxRecords int
+	maxAge     time.Duration
+	budget     *bufferBudget
+	batches    map[string]*signalBatch[T]
+	appendFunc func([]T, ...T) []T
+	countFunc  func([]T) int
+}
+
+func newBatchBuffer[T proto.Message](cfg *StorageBatchConfig, budget *bufferBudget, appendFunc func([]T, ...T) []T, countFunc func([]T) int) *batchBuffer[T] {
+	if cfg.Enable == nil || !*cfg.Enable {
+		return nil
+	}
+	return &batchBuffer[T]{
+		maxBytes:   cfg.MaxBytes,
+		maxRecords: cfg.MaxRecords,
+		maxAge:     cfg.maxAge,
+		budget:     budget,
+		batches:    make(map[string]*signalBatch[T]),
+		appendFunc: appendFunc,
+		countFunc:  countFunc,
+	}
+}
+
+// add appends data to the batch of the partition.
+// when the batch gets full, add takes it out, and returns the full batch with data and the batch before data is appended.
+// if the full batch fails to write, the caller requeues only the previous batch, and returns the error to the exporter,
+// so that data is not written twice when the exporter retries.
+// add fails with ResourceExhausted when the buffer is full.
+func (b *batchBuffer[T]) add(partition string, data []T) (*signalBatch[T], *signalBatch[T], error) {
+	var bytes int
+	for _, d := range data {
+		bytes += proto.Size(d)
+	}
+	records := b.countFunc(data)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.budget.reserve(bytes) {
+		return nil, nil, status.Error(codes.ResourceExhausted, "write buffer is full, storage may be unavailable")
+	}
+	prev, ok := b.batches[partition]
+	if !ok {
+		prev = &signalBatch[T]{
+			partition: partition,
+			createdAt: time.Now(),
+		}
+	}
+	if prev.bytes+bytes < b.maxBytes && prev.records+records < b.maxRecords {
+		prev.data = b.appendFunc(prev.data, data...)
+		prev.bytes += bytes
+		prev.records += records
+		b.batches[partition] = prev
+		return nil, nil, nil
+	}
+	delete(b.batches, partition)
+	b.budget.release(prev.bytes + bytes)
+	// appending merges data into elements of dst, so the previous batch is kept by cloning elements.
+	full := &signalBatch[T]{
+		partition: partition,
+		data:      make([]T, 0, len(prev.data)+len(data)),
+		bytes:     prev.bytes + bytes,
+		records:   prev.records + records,
+		createdAt: prev.createdAt,
+	}
+	for _, d := range prev.data {
+		full.data = append(full.data, proto.Clone(d).(T))
+	}
+	full.data = b.appendFunc(full.data, data...)
+	if prev.records == 0 {
+		prev = nil
+	}
+	return full, prev, nil
+}
+
+// take returns batches older than max age, or all batches when all is true.
+func (b *batchBuffer[T]) take(all bool) []*signalBatch[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	batches := make([]*signalBatch[T], 0)
+	for partition, batch := range b.batches {
+		if !all && time.Since(batch.createdAt) < b.maxAge {
+			continue
+		}
+		delete(b.batches, partition)
+		b.budget.release(batch.bytes)
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// requeue puts back the batch failed to write, so that it is retried by the next flush.
+// the batch is dropped when the buffer has no room for it, requeue reports whether the batch is put back.
+func (b *batchBuffer[T]) requeue(batch *signalBatch[T]) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.budget.reserve(batch.bytes) {
+		return false
+	}
+	current, ok := b.batches[batch.partition]
+	if !ok {
+		b.batches[batch.partition] = batch
+		return true
+	}
+	current.data = b.appendFunc(batch.data, current.data...)
+	current.bytes += batch.bytes
+	current.records += batch.records
+	current.createdAt = batch.createdAt
+	return true
+}
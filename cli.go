@@ -1,10 +1,13 @@
 package oteleport
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,7 +15,10 @@ import (
 	"github.com/fatih/color"
 	"github.com/mashiike/go-otlp-helper/otlp"
 	"github.com/mashiike/oteleport/pkg/client"
+	oteleportpb "github.com/mashiike/oteleport/proto"
 	"github.com/mashiike/slogutils"
+	"github.com/samber/lo"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
 )
 
 type ServerCLIOptions struct {
@@ -23,8 +29,34 @@ type ServerCLIOptions struct {
 	LogLevel string `help:"log level (debug, info, warn, error)" default:"info" enum:"debug,info,warn,error" env:"OTELPORT_LOG_LEVEL"`
 	Color    *bool  `help:"enable colored output" env:"OTELPORT_COLOR" negatable:""`
 
-	Serve   struct{} `cmd:"" help:"start oteleport server" default:"1"`
-	Version struct{} `cmd:"version" help:"show version"`
+	Serve   struct{}             `cmd:"" help:"start oteleport server" default:"1"`
+	Compact ServerCompactOptions `cmd:"" help:"merge small objects in each partition into larger ones"`
+	Purge   ServerPurgeOptions   `cmd:"" help:"delete partitions older than the storage retention"`
+	Ranges  ServerRangesOptions  `cmd:"" help:"show time ranges of stored signals by the partition manifest"`
+	HashKey ServerHashKeyOptions `cmd:"" help:"hash a secret key for secret_key_hash of access keys"`
+	Version struct{}             `cmd:"version" help:"show version"`
+}
+
+type ServerCompactOptions struct {
+	Signal   string    `help:"signal to compact (traces, metrics, logs)" required:"" enum:"traces,metrics,logs"`
+	From     time.Time `help:"compact partitions newer than this time. RFC3339 format" required:"" format:"2006-01-02T15:04:05Z"`
+	To       time.Time `help:"compact partitions older than this time. RFC3339 format" required:"" format:"2006-01-02T15:04:05Z"`
+	MaxBytes int64     `help:"max total size of source objects merged into one object" default:"67108864"`
+	Tenant   string    `help:"tenant to compact, default is signals not of tenants"`
+}
+
+type ServerRangesOptions struct {
+	Signal  string `help:"signal to show (traces, metrics, logs)" required:"" enum:"traces,metrics,logs"`
+	Rebuild bool   `help:"rebuild the manifest from stored objects before showing"`
+	Tenant  string `help:"tenant to show, default is signals not of tenants"`
+}
+
+type ServerPurgeOptions struct {
+	DryRun bool `help:"report objects to delete without deleting"`
+}
+
+type ServerHashKeyOptions struct {
+	Generate bool `help:"generate a new secret key, instead of reading the secret key from stdin"`
 }
 
 type ServerCLIParseFunc func([]string) (string, *ServerCLIOptions, func(), error)
@@ -76,28 +108,125 @@ func dispatchServerCLI(ctx context.Context, sub string, usage func(), opts *Serv
 	case "version", "":
 		fmt.Println("oteleport-server", Version)
 		return nil
+	case "hash-key":
+		return runHashKey(opts.HashKey)
 	}
 
+	cfg := DefaultServerConfig()
+	if err := cfg.Load(opts.ConfigPath, &LoadOptions{
+		ExtVars:  opts.ExtStr,
+		ExtCodes: opts.ExtCode,
+	}); err != nil {
+		return err
+	}
 	switch sub {
 	case "serve":
-		cfg := DefaultServerConfig()
-		if err := cfg.Load(opts.ConfigPath, &LoadOptions{
-			ExtVars:  opts.ExtStr,
-			ExtCodes: opts.ExtCode,
-		}); err != nil {
-			return err
-		}
 		s, err := NewServer(cfg)
 		if err != nil {
 			return err
 		}
 		return s.Run(ctx)
+	case "compact":
+		repo, err := NewSignalRepository(&cfg.Storage)
+		if err != nil {
+			return err
+		}
+		result, err := repo.ForTenant(opts.Compact.Tenant).Compact(ctx, &CompactOptions{
+			Signal:    opts.Compact.Signal,
+			StartTime: opts.Compact.From,
+			EndTime:   opts.Compact.To,
+			MaxBytes:  opts.Compact.MaxBytes,
+		})
+		if err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "compacted", "tenant", opts.Compact.Tenant, "signal", opts.Compact.Signal, "source_objects", result.SourceObjects, "source_bytes", result.SourceBytes, "compacted_objects", result.CompactedObjects)
+		return nil
+	case "purge":
+		repo, err := NewSignalRepository(&cfg.Storage)
+		if err != nil {
+			return err
+		}
+		results, err := repo.Purge(ctx, &PurgeOptions{
+			DryRun: opts.Purge.DryRun,
+		})
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			slog.WarnContext(ctx, "no storage retention configured")
+		}
+		for _, result := range results {
+			line := fmt.Sprintf("%s\tcutoff=%s\tobjects=%d\tbytes=%d", result.Signal, result.Cutoff.Format(time.RFC3339), result.Objects, result.Bytes)
+			if result.Tenant != "" {
+				line += "\ttenant=" + result.Tenant
+			}
+			fmt.Println(line)
+		}
+		return nil
+	case "ranges":
+		repo, err := NewSignalRepository(&cfg.Storage)
+		if err != nil {
+			return err
+		}
+		repo = repo.ForTenant(opts.Ranges.Tenant)
+		if opts.Ranges.Rebuild {
+			n, err := repo.RebuildManifest(ctx, opts.Ranges.Signal)
+			if err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "rebuilt manifest", "signal", opts.Ranges.Signal, "partitions", n)
+		}
+		ranges, err := repo.TimeRanges(ctx, opts.Ranges.Signal)
+		if err != nil {
+			return err
+		}
+		for _, r := range ranges {
+			fmt.Printf("%s\t%s\n", r.StartTime.Format(time.RFC3339), r.EndTime.Format(time.RFC3339))
+		}
+		return nil
 	default:
 		usage()
 	}
 	return nil
 }
 
+// runHashKey prints the hash of the secret key read from stdin, not from args, to keep the secret out of the shell history.
+// with --generate, a new secret key is printed before the hash.
+func runHashKey(opts ServerHashKeyOptions) error {
+	var secretKey string
+	if opts.Generate {
+		var err error
+		secretKey, err = GenerateSecretKey()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("secret_key: %s\n", secretKey)
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return fmt.Errorf("failed to read secret key: %w", err)
+			}
+			return fmt.Errorf("secret key is required in stdin")
+		}
+		secretKey = strings.TrimSpace(scanner.Text())
+		if secretKey == "" {
+			return fmt.Errorf("secret key is required in stdin")
+		}
+	}
+	hash, err := HashSecretKey(secretKey)
+	if err != nil {
+		return err
+	}
+	if opts.Generate {
+		fmt.Printf("secret_key_hash: %s\n", hash)
+		return nil
+	}
+	fmt.Println(hash)
+	return nil
+}
+
 type ClientCLIOptions struct {
 	LogLevel string `help:"log level (debug, info, warn, error)" default:"info" enum:"debug,info,warn,error" env:"OTELPORT_LOG_LEVEL"`
 	Color    *bool  `help:"enable colored output" env:"OTELPORT_COLOR"`
@@ -112,21 +241,152 @@ type ClientCLIOptions struct {
 	ClientSignalOutputOptions
 
 	Version struct{}                    `cmd:"version" help:"show version"`
-	Traces  ClientTracesCommandOptions  `cmd:"traces" help:"traces subcommand"`
+	Traces  ClientTracesCommands        `cmd:"traces" help:"traces subcommand"`
 	Metrics ClientMetricsCommandOptions `cmd:"metrics" help:"metrics subcommand"`
 	Logs    ClientLogsCommandOptions    `cmd:"logs" help:"logs subcommand"`
 }
 
+type ClientTracesCommands struct {
+	Fetch ClientTracesCommandOptions    `cmd:"" help:"fetch traces data in the time range (default)" default:"withargs"`
+	Get   ClientTracesGetCommandOptions `cmd:"" help:"get all spans of a trace"`
+}
+
+type ClientTracesGetCommandOptions struct {
+	TraceID   string     `arg:"" name:"trace-id" help:"hex encoded trace id"`
+	StartTime *time.Time `help:"time hint, search spans newer than this time. RFC3339 format (default: 24 hours before end time)" format:"2006-01-02T15:04:05Z"`
+	EndTime   *time.Time `help:"time hint, search spans older than this time. RFC3339 format (default: now)" format:"2006-01-02T15:04:05Z"`
+}
+
 type ClientTracesCommandOptions struct {
 	ClientTimeRangeOptions
+	ClientFilterOptions
+	ClientTimeShiftOptions
+	ClientReplayOptions
+	SpanNames []string `name:"span-name" help:"return spans with this name (can be repeated)"`
+	TraceIDs  []string `name:"trace-id" help:"return spans of this hex encoded trace id (can be repeated)"`
+}
+
+func (o *ClientTracesCommandOptions) Filter() *oteleportpb.TracesFilter {
+	f := &oteleportpb.TracesFilter{
+		ResourceAttributes: o.ResourceAttributeMatchers(),
+		SpanNames:          o.SpanNames,
+		Attributes:         o.AttributeMatchers(),
+		TraceIds:           o.TraceIDs,
+	}
+	if len(f.ResourceAttributes) == 0 && len(f.SpanNames) == 0 && len(f.Attributes) == 0 && len(f.TraceIds) == 0 {
+		return nil
+	}
+	return f
 }
 
 type ClientMetricsCommandOptions struct {
 	ClientTimeRangeOptions
+	ClientFilterOptions
+	ClientTimeShiftOptions
+	ClientReplayOptions
+	MetricNames []string `name:"metric-name" help:"return data points of this metric name (can be repeated)"`
+}
+
+func (o *ClientMetricsCommandOptions) Filter() *oteleportpb.MetricsFilter {
+	f := &oteleportpb.MetricsFilter{
+		ResourceAttributes: o.ResourceAttributeMatchers(),
+		MetricNames:        o.MetricNames,
+		Attributes:         o.AttributeMatchers(),
+	}
+	if len(f.ResourceAttributes) == 0 && len(f.MetricNames) == 0 && len(f.Attributes) == 0 {
+		return nil
+	}
+	return f
 }
 
 type ClientLogsCommandOptions struct {
 	ClientTimeRangeOptions
+	ClientFilterOptions
+	ClientTimeShiftOptions
+	ClientReplayOptions
+	MinSeverity string `help:"return log records with this severity or higher. like INFO, WARN, ERROR or severity number"`
+}
+
+func (o *ClientLogsCommandOptions) Filter() (*oteleportpb.LogsFilter, error) {
+	f := &oteleportpb.LogsFilter{
+		ResourceAttributes: o.ResourceAttributeMatchers(),
+		Attributes:         o.AttributeMatchers(),
+	}
+	if o.MinSeverity != "" {
+		severity, err := parseSeverityNumber(o.MinSeverity)
+		if err != nil {
+			return nil, err
+		}
+		f.MinSeverityNumber = severity
+	}
+	if len(f.ResourceAttributes) == 0 && len(f.Attributes) == 0 && f.MinSeverityNumber == logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func parseSeverityNumber(str string) (logspb.SeverityNumber, error) {
+	if n, err := strconv.Atoi(str); err == nil {
+		if _, ok := logspb.SeverityNumber_name[int32(n)]; !ok {
+			return 0, fmt.Errorf("invalid severity number: %d", n)
+		}
+		return logspb.SeverityNumber(n), nil
+	}
+	if n, ok := logspb.SeverityNumber_value["SEVERITY_NUMBER_"+strings.ToUpper(str)]; ok {
+		return logspb.SeverityNumber(n), nil
+	}
+	return 0, fmt.Errorf("invalid severity: %s", str)
+}
+
+type ClientFilterOptions struct {
+	Service            string            `help:"return signals of this service.name resource attribute" env:"OTELPORT_SERVICE"`
+	ResourceAttributes map[string]string `name:"resource-attribute" help:"return signals with this resource attribute. like key=value"`
+	Attributes         map[string]string `name:"attribute" help:"return signals with this attribute. like key=value"`
+}
+
+func (o *ClientFilterOptions) ResourceAttributeMatchers() []*oteleportpb.AttributeMatcher {
+	attrs := make(map[string]string, len(o.ResourceAttributes)+1)
+	for k, v := range o.ResourceAttributes {
+		attrs[k] = v
+	}
+	if o.Service != "" {
+		attrs["service.name"] = o.Service
+	}
+	return attributeMatchers(attrs)
+}
+
+func (o *ClientFilterOptions) AttributeMatchers() []*oteleportpb.AttributeMatcher {
+	return attributeMatchers(o.Attributes)
+}
+
+func attributeMatchers(attrs map[string]string) []*oteleportpb.AttributeMatcher {
+	if len(attrs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	matchers := make([]*oteleportpb.AttributeMatcher, 0, len(keys))
+	for _, k := range keys {
+		matchers = append(matchers, &oteleportpb.AttributeMatcher{
+			Key:   k,
+			Value: attrs[k],
+		})
+	}
+	return matchers
+}
+
+type ClientTimeShiftOptions struct {
+	ShiftToNow bool          `help:"shift timestamps so that the start time is at the current time, relative timing is preserved" xor:"time-shift"`
+	TimeOffset time.Duration `help:"shift timestamps by this duration, like 168h or -30m" xor:"time-shift"`
+}
+
+type ClientReplayOptions struct {
+	Paced               bool    `help:"output signals at the original cadence of their timestamps"`
+	Speed               float64 `help:"speed multiplier of paced output, like 2 or 10" default:"1"`
+	MaxSignalsPerSecond int     `name:"max-signals-per-second" help:"max signals per second to output (0: unlimited)"`
 }
 
 type ClientTimeRangeOptions struct {
@@ -186,7 +446,10 @@ func ParseClientCLI(args []string) (string, *ClientCLIOptions, func(), error) {
 		parser.FatalIfErrorf(err)
 		return "", nil, nil, fmt.Errorf("failed to parse args: %w", err)
 	}
-	sub := strings.Fields(c.Command())[0]
+	// sub is a command path without positional arguments, like "traces get"
+	sub := strings.Join(lo.Filter(strings.Fields(c.Command()), func(s string, _ int) bool {
+		return !strings.HasPrefix(s, "<")
+	}), " ")
 	return sub, &opts, func() {
 		if err := c.PrintUsage(true); err != nil {
 			slog.WarnContext(context.Background(), "failed to print usage", "message", err)
@@ -240,10 +503,22 @@ func dispatchClientCLI(ctx context.Context, sub string, usage func(), opts *Clie
 	if err != nil {
 		return err
 	}
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := app.Stop(stopCtx); err != nil {
+			slog.WarnContext(ctx, "failed to stop client", "message", err.Error())
+		}
+	}()
 
 	switch sub {
-	case "traces":
-		return app.FetchTracesData(ctx, &opts.Traces)
+	case "traces", "traces fetch":
+		return app.FetchTracesData(ctx, &opts.Traces.Fetch)
+	case "traces get":
+		return app.GetTrace(ctx, &opts.Traces.Get)
 	case "metrics":
 		return app.FetchMetricsData(ctx, &opts.Metrics)
 	case "logs":
@@ -288,9 +563,9 @@ type ClientSignalOutputOptions struct {
 	OtelExporterOTLPLogsEndpoint    string `help:"exporter logs endpoint" default:"" env:"OTEL_EXPORTER_OTLP_LOGS_ENDPOINT" group:"OpenTelemetry Exporter Parameters" json:"otlp_logs_endpoint"`
 
 	OtelExporterOTLPProtocol        string `help:"exporter protocol" default:"grpc" enum:"grpc,http" env:"OTEL_EXPORTER_OTLP_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_protocol"`
-	OtelExporterOTLPTracesProtocol  string `help:"exporter traces protocol" default:"grpc" enum:"grpc,http" env:"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_traces_protocol"`
-	OtelExporterOTLPMetricsProtocol string `help:"exporter metrics protocol" default:"grpc" enum:"grpc,http" env:"OTEL_EXPORTER_OTLP_METRICS_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_metrics_protocol"`
-	OtelExporterOTLPLogsProtocol    string `help:"exporter logs protocol" default:"grpc" enum:"grpc,http" env:"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_logs_protocol"`
+	OtelExporterOTLPTracesProtocol  string `help:"exporter traces protocol" enum:",grpc,http" env:"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_traces_protocol"`
+	OtelExporterOTLPMetricsProtocol string `help:"exporter metrics protocol" enum:",grpc,http" env:"OTEL_EXPORTER_OTLP_METRICS_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_metrics_protocol"`
+	OtelExporterOTLPLogsProtocol    string `help:"exporter logs protocol" enum:",grpc,http" env:"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL" group:"OpenTelemetry Exporter Parameters" json:"otlp_logs_protocol"`
 
 	OtelExporterOTLPHeaders        map[string]string `help:"exporter headers" env:"OTEL_EXPORTER_OTLP_HEADERS" group:"OpenTelemetry Exporter Parameters" json:"otlp_headers"`
 	OtelExporterOTLPTracesHeaders  map[string]string `help:"exporter traces headers" env:"OTEL_EXPORTER_OTLP_TRACES_HEADERS" group:"OpenTelemetry Exporter Parameters" json:"otlp_traces_headers"`
@@ -303,9 +578,9 @@ type ClientSignalOutputOptions struct {
 	OtelExporterOTLPLogsCompression    string `help:"exporter logs compression" default:"none" enum:"gzip,none" env:"OTEL_EXPORTER_OTLP_LOGS_COMPRESSION" group:"OpenTelemetry Exporter Parameters" json:"otlp_logs_compression"`
 
 	OtelExporterOTLPTimeout        time.Duration `help:"exporter timeout" default:"10s" env:"OTEL_EXPORTER_OTLP_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_timeout"`
-	OtelExporterOTLPTracesTimeout  time.Duration `help:"exporter traces timeout" default:"" env:"OTEL_EXPORTER_OTLP_TRACES_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_traces_timeout"`
-	OtelExporterOTLPMetricsTimeout time.Duration `help:"exporter metrics timeout" default:"" env:"OTEL_EXPORTER_OTLP_METRICS_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_metrics_timeout"`
-	OtelExporterOTLPLogsTimeout    time.Duration `help:"exporter logs timeout" default:"" env:"OTEL_EXPORTER_OTLP_LOGS_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_logs_timeout"`
+	OtelExporterOTLPTracesTimeout  time.Duration `help:"exporter traces timeout" env:"OTEL_EXPORTER_OTLP_TRACES_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_traces_timeout"`
+	OtelExporterOTLPMetricsTimeout time.Duration `help:"exporter metrics timeout" env:"OTEL_EXPORTER_OTLP_METRICS_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_metrics_timeout"`
+	OtelExporterOTLPLogsTimeout    time.Duration `help:"exporter logs timeout" env:"OTEL_EXPORTER_OTLP_LOGS_TIMEOUT" group:"OpenTelemetry Exporter Parameters" json:"otlp_logs_timeout"`
 }
 
 func (o ClientSignalOutputOptions) OTLPClientOptions() []otlp.ClientOption {
@@ -321,7 +596,7 @@ func (o ClientSignalOutputOptions) OTLPClientOptions() []otlp.ClientOption {
 		opts = append(opts, otlp.WithLogsEndpoint(o.OtelExporterOTLPLogsEndpoint))
 	}
 	if o.OtelExporterOTLPProtocol != "" {
-		opts = append(opts, otlp.WithTracesProtocol(o.OtelExporterOTLPProtocol))
+		opts = append(opts, otlp.WithProtocol(o.OtelExporterOTLPProtocol))
 	}
 	if o.OtelExporterOTLPTracesProtocol != "" {
 		opts = append(opts, otlp.WithTracesProtocol(o.OtelExporterOTLPTracesProtocol))
@@ -81,6 +81,28 @@ func NewClientApp(p *Profile) (*ClientApp, error) {
 	return app, nil
 }
 
+// Start starts the otlp exporter client, when the exporter endpoint is configured.
+func (a *ClientApp) Start(ctx context.Context) error {
+	if a.otlpClient == nil {
+		return nil
+	}
+	if err := a.otlpClient.Start(ctx); err != nil {
+		return oops.Wrapf(err, "failed to start otlp client")
+	}
+	return nil
+}
+
+// Stop waits for in-flight exports and stops the otlp exporter client.
+func (a *ClientApp) Stop(ctx context.Context) error {
+	if a.otlpClient == nil {
+		return nil
+	}
+	if err := a.otlpClient.Stop(ctx); err != nil {
+		return oops.Wrapf(err, "failed to stop otlp client")
+	}
+	return nil
+}
+
 var (
 	followPollingInterval = 5 * time.Second
 	fetchPollingInterval  = 200 * time.Millisecond
@@ -88,6 +110,14 @@ var (
 
 func (a *ClientApp) FetchTracesData(ctx context.Context, opts *ClientTracesCommandOptions) error {
 	startTimeUnixNano, endTimeUnixNano := opts.TimeRangeUnixNano()
+	shifter, err := newTimeShifter(&opts.ClientTimeShiftOptions, startTimeUnixNano)
+	if err != nil {
+		return oops.Wrapf(err, "invalid time shift options")
+	}
+	pacer, err := newReplayPacer(&opts.ClientReplayOptions)
+	if err != nil {
+		return oops.Wrapf(err, "invalid replay options")
+	}
 	var follow bool
 	if endTimeUnixNano == 0 {
 		follow = true
@@ -99,6 +129,7 @@ func (a *ClientApp) FetchTracesData(ctx context.Context, opts *ClientTracesComma
 			StartTimeUnixNano: uint64(startTimeUnixNano),
 			EndTimeUnixNano:   uint64(endTimeUnixNano),
 			Limit:             100,
+			Filter:            opts.Filter(),
 		})
 		for p.HasMorePages() {
 			select {
@@ -114,22 +145,29 @@ func (a *ClientApp) FetchTracesData(ctx context.Context, opts *ClientTracesComma
 				slog.DebugContext(ctx, "no more spans available")
 				continue
 			}
-			switch {
-			case a.otlpClient != nil:
-				if err := a.otlpClient.UploadTraces(ctx, resp.GetResourceSpans()); err != nil {
-					slog.WarnContext(ctx, "failed to export trace data", "message", err.Error())
-				}
-			default:
-				tracesData := &tracepb.TracesData{
-					ResourceSpans: resp.GetResourceSpans(),
-				}
+			shifter.ShiftResourceSpans(resp.GetResourceSpans())
+			err = pacer.ReplayResourceSpans(ctx, resp.GetResourceSpans(), func(resourceSpans []*tracepb.ResourceSpans) error {
+				switch {
+				case a.otlpClient != nil:
+					if err := a.otlpClient.UploadTraces(ctx, resourceSpans); err != nil {
+						slog.WarnContext(ctx, "failed to export trace data", "message", err.Error())
+					}
+				default:
+					tracesData := &tracepb.TracesData{
+						ResourceSpans: resourceSpans,
+					}
 
-				bs, err := otlp.MarshalJSON(tracesData)
-				if err != nil {
-					slog.WarnContext(ctx, "failed to marshal fetch traces data response", "message", err.Error())
-					return nil
+					bs, err := otlp.MarshalJSON(tracesData)
+					if err != nil {
+						slog.WarnContext(ctx, "failed to marshal fetch traces data response", "message", err.Error())
+						return nil
+					}
+					fmt.Println(string(bs))
 				}
-				fmt.Println(string(bs))
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 			time.Sleep(fetchPollingInterval)
 		}
@@ -148,8 +186,43 @@ func (a *ClientApp) FetchTracesData(ctx context.Context, opts *ClientTracesComma
 	return nil
 }
 
+func (a *ClientApp) GetTrace(ctx context.Context, opts *ClientTracesGetCommandOptions) error {
+	req := &oteleportpb.GetTraceRequest{
+		TraceId: opts.TraceID,
+	}
+	if opts.StartTime != nil {
+		req.StartTimeUnixNano = uint64(opts.StartTime.UnixNano())
+	}
+	if opts.EndTime != nil {
+		req.EndTimeUnixNano = uint64(opts.EndTime.UnixNano())
+	}
+	resp, err := a.c.GetTrace(ctx, req)
+	if err != nil {
+		return err
+	}
+	if a.otlpClient != nil {
+		return a.otlpClient.UploadTraces(ctx, resp.GetResourceSpans())
+	}
+	bs, err := otlp.MarshalJSON(&tracepb.TracesData{
+		ResourceSpans: resp.GetResourceSpans(),
+	})
+	if err != nil {
+		return oops.Wrapf(err, "failed to marshal get trace response")
+	}
+	fmt.Println(string(bs))
+	return nil
+}
+
 func (a *ClientApp) FetchMetricsData(ctx context.Context, opts *ClientMetricsCommandOptions) error {
 	startTimeUnixNano, endTimeUnixNano := opts.TimeRangeUnixNano()
+	shifter, err := newTimeShifter(&opts.ClientTimeShiftOptions, startTimeUnixNano)
+	if err != nil {
+		return oops.Wrapf(err, "invalid time shift options")
+	}
+	pacer, err := newReplayPacer(&opts.ClientReplayOptions)
+	if err != nil {
+		return oops.Wrapf(err, "invalid replay options")
+	}
 	var follow bool
 	if endTimeUnixNano == 0 {
 		follow = true
@@ -161,6 +234,7 @@ func (a *ClientApp) FetchMetricsData(ctx context.Context, opts *ClientMetricsCom
 			StartTimeUnixNano: uint64(startTimeUnixNano),
 			EndTimeUnixNano:   uint64(endTimeUnixNano),
 			Limit:             100,
+			Filter:            opts.Filter(),
 		})
 		for p.HasMorePages() {
 			select {
@@ -176,21 +250,28 @@ func (a *ClientApp) FetchMetricsData(ctx context.Context, opts *ClientMetricsCom
 				slog.DebugContext(ctx, "no more metrics available")
 				continue
 			}
-			switch {
-			case a.otlpClient != nil:
-				if err := a.otlpClient.UploadMetrics(ctx, resp.GetResourceMetrics()); err != nil {
-					slog.WarnContext(ctx, "failed to export metrics data", "message", err.Error())
-				}
-			default:
-				metricsData := &metricspb.MetricsData{
-					ResourceMetrics: resp.GetResourceMetrics(),
-				}
-				bs, err := otlp.MarshalJSON(metricsData)
-				if err != nil {
-					slog.WarnContext(ctx, "failed to marshal fetch metrics data response", "message", err.Error())
-				}
+			shifter.ShiftResourceMetrics(resp.GetResourceMetrics())
+			err = pacer.ReplayResourceMetrics(ctx, resp.GetResourceMetrics(), func(resourceMetrics []*metricspb.ResourceMetrics) error {
+				switch {
+				case a.otlpClient != nil:
+					if err := a.otlpClient.UploadMetrics(ctx, resourceMetrics); err != nil {
+						slog.WarnContext(ctx, "failed to export metrics data", "message", err.Error())
+					}
+				default:
+					metricsData := &metricspb.MetricsData{
+						ResourceMetrics: resourceMetrics,
+					}
+					bs, err := otlp.MarshalJSON(metricsData)
+					if err != nil {
+						slog.WarnContext(ctx, "failed to marshal fetch metrics data response", "message", err.Error())
+					}
 
-				fmt.Println(string(bs))
+					fmt.Println(string(bs))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 			time.Sleep(fetchPollingInterval)
 		}
@@ -211,6 +292,18 @@ func (a *ClientApp) FetchMetricsData(ctx context.Context, opts *ClientMetricsCom
 
 func (a *ClientApp) FetchLogsData(ctx context.Context, opts *ClientLogsCommandOptions) error {
 	startTimeUnixNano, endTimeUnixNano := opts.TimeRangeUnixNano()
+	shifter, err := newTimeShifter(&opts.ClientTimeShiftOptions, startTimeUnixNano)
+	if err != nil {
+		return oops.Wrapf(err, "invalid time shift options")
+	}
+	pacer, err := newReplayPacer(&opts.ClientReplayOptions)
+	if err != nil {
+		return oops.Wrapf(err, "invalid replay options")
+	}
+	filter, err := opts.Filter()
+	if err != nil {
+		return oops.Wrapf(err, "invalid filter")
+	}
 	var follow bool
 	if endTimeUnixNano == 0 {
 		follow = true
@@ -222,6 +315,7 @@ func (a *ClientApp) FetchLogsData(ctx context.Context, opts *ClientLogsCommandOp
 			StartTimeUnixNano: uint64(startTimeUnixNano),
 			EndTimeUnixNano:   uint64(endTimeUnixNano),
 			Limit:             100,
+			Filter:            filter,
 		})
 		for p.HasMorePages() {
 			select {
@@ -237,19 +331,29 @@ func (a *ClientApp) FetchLogsData(ctx context.Context, opts *ClientLogsCommandOp
 				slog.DebugContext(ctx, "no more logs available")
 				continue
 			}
-			if a.outputOpts.OtelExporterOTLPEndpoint != "" {
-				return oops.Errorf("signal export to otel exporter is not implemented yet")
-			}
-			logsData := &logspb.LogsData{
-				ResourceLogs: resp.GetResourceLogs(),
-			}
+			shifter.ShiftResourceLogs(resp.GetResourceLogs())
+			err = pacer.ReplayResourceLogs(ctx, resp.GetResourceLogs(), func(resourceLogs []*logspb.ResourceLogs) error {
+				switch {
+				case a.otlpClient != nil:
+					if err := a.otlpClient.UploadLogs(ctx, resourceLogs); err != nil {
+						slog.WarnContext(ctx, "failed to export logs data", "message", err.Error())
+					}
+				default:
+					logsData := &logspb.LogsData{
+						ResourceLogs: resourceLogs,
+					}
+					bs, err := otlp.MarshalJSON(logsData)
+					if err != nil {
+						slog.WarnContext(ctx, "failed to marshal fetch logs data response", "message", err.Error())
+					}
 
-			bs, err := otlp.MarshalJSON(logsData)
+					fmt.Println(string(bs))
+				}
+				return nil
+			})
 			if err != nil {
-				slog.WarnContext(ctx, "failed to marshal fetch logs data response", "message", err.Error())
+				return err
 			}
-
-			fmt.Println(string(bs))
 			time.Sleep(fetchPollingInterval)
 		}
 		if !follow {
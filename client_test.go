@@ -0,0 +1,286 @@
+package oteleport_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mashiike/go-otlp-helper/otlp"
+	"github.com/mashiike/oteleport"
+	oteleportclient "github.com/mashiike/oteleport/pkg/client"
+	oteleportpb "github.com/mashiike/oteleport/proto"
+	"github.com/stretchr/testify/require"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func startTestMemoryServer(t *testing.T, ctx context.Context, opts ...func(*oteleport.ServerConfig)) *oteleport.ServerConfig {
+	t.Helper()
+	cfg := oteleport.DefaultServerConfig()
+	require.NoError(t, cfg.Load("testdata/default.jsonnet", nil))
+	grpcOTLPLis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg.OTLP.GRPC.Listener = grpcOTLPLis
+	cfg.OTLP.HTTP.Enable = oteleport.Pointer(false)
+	httpAPILis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg.API.HTTP.Listener = httpAPILis
+	cfg.Storage.Location = "memory://"
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+	s, err := oteleport.NewServer(cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := s.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+	return cfg
+}
+
+func TestClientApp__ExportLogs(t *testing.T) {
+	ctx := context.Background()
+	srcCfg := startTestMemoryServer(t, ctx)
+	dstCfg := startTestMemoryServer(t, ctx)
+
+	bs, err := os.ReadFile("testdata/logs.json")
+	require.NoError(t, err)
+	var logs logspb.LogsData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &logs))
+	otlpClient, err := otlp.NewClient("http://" + srcCfg.OTLP.GRPC.Address)
+	require.NoError(t, err)
+	require.NoError(t, otlpClient.Start(ctx))
+	require.NoError(t, otlpClient.UploadLogs(ctx, logs.GetResourceLogs()))
+	require.NoError(t, otlpClient.Stop(ctx))
+
+	app, err := oteleport.NewClientApp(&oteleport.Profile{
+		Profile: &oteleportclient.Profile{
+			Endpoint: "http://" + srcCfg.API.HTTP.Address,
+		},
+		Output: oteleport.ClientSignalOutputOptions{
+			OtelExporterOTLPEndpoint: "http://" + dstCfg.OTLP.GRPC.Address,
+			OtelExporterOTLPProtocol: "grpc",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, app.Start(ctx))
+	startTime := time.Unix(0, 1544712660000000000)
+	endTime := time.Unix(0, 1544712661000000000)
+	err = app.FetchLogsData(ctx, &oteleport.ClientLogsCommandOptions{
+		ClientTimeRangeOptions: oteleport.ClientTimeRangeOptions{
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, app.Stop(ctx))
+
+	dstClient, err := oteleportclient.New(&oteleportclient.Profile{
+		Endpoint: "http://" + dstCfg.API.HTTP.Address,
+	})
+	require.NoError(t, err)
+	resp, err := dstClient.FetchLogsData(ctx, &oteleportpb.FetchLogsDataRequest{
+		StartTimeUnixNano: uint64(startTime.UnixNano()),
+		EndTimeUnixNano:   uint64(endTime.UnixNano()),
+	})
+	require.NoError(t, err)
+	actualJSON, err := otlp.MarshalJSON(&logspb.LogsData{
+		ResourceLogs: resp.GetResourceLogs(),
+	})
+	require.NoError(t, err)
+	expectedJSON, err := otlp.MarshalJSON(&logs)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestClientApp__ExportTraces__TimeShift(t *testing.T) {
+	ctx := context.Background()
+	srcCfg := startTestMemoryServer(t, ctx)
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	otlpClient, err := otlp.NewClient("http://" + srcCfg.OTLP.GRPC.Address)
+	require.NoError(t, err)
+	require.NoError(t, otlpClient.Start(ctx))
+	require.NoError(t, otlpClient.UploadTraces(ctx, traces.GetResourceSpans()))
+	require.NoError(t, otlpClient.Stop(ctx))
+	startTime := time.Unix(0, 1544712660000000000)
+	endTime := time.Unix(0, 1544712661000000000)
+
+	cases := []struct {
+		name      string
+		opts      oteleport.ClientTimeShiftOptions
+		startTime time.Time
+		endTime   time.Time
+		fetchFrom time.Time
+		fetchTo   time.Time
+	}{
+		{
+			name:      "time_offset",
+			opts:      oteleport.ClientTimeShiftOptions{TimeOffset: 24 * time.Hour},
+			startTime: startTime,
+			endTime:   endTime,
+			fetchFrom: startTime.Add(24 * time.Hour),
+			fetchTo:   endTime.Add(24 * time.Hour),
+		},
+		{
+			name:      "shift_to_now",
+			opts:      oteleport.ClientTimeShiftOptions{ShiftToNow: true},
+			startTime: startTime,
+			endTime:   endTime,
+			fetchFrom: time.Now().Add(-time.Minute),
+			fetchTo:   time.Now().Add(time.Minute),
+		},
+		{
+			// the start time of the fetch is shifted to now, not the first signal.
+			name:      "shift_to_now_from_start_time",
+			opts:      oteleport.ClientTimeShiftOptions{ShiftToNow: true},
+			startTime: startTime.Add(-time.Hour),
+			endTime:   endTime.Add(time.Minute),
+			fetchFrom: time.Now().Add(time.Hour - time.Minute),
+			fetchTo:   time.Now().Add(time.Hour + time.Minute),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dstCfg := startTestMemoryServer(t, ctx)
+			app, err := oteleport.NewClientApp(&oteleport.Profile{
+				Profile: &oteleportclient.Profile{
+					Endpoint: "http://" + srcCfg.API.HTTP.Address,
+				},
+				Output: oteleport.ClientSignalOutputOptions{
+					OtelExporterOTLPEndpoint: "http://" + dstCfg.OTLP.GRPC.Address,
+					OtelExporterOTLPProtocol: "grpc",
+				},
+			})
+			require.NoError(t, err)
+			require.NoError(t, app.Start(ctx))
+			err = app.FetchTracesData(ctx, &oteleport.ClientTracesCommandOptions{
+				ClientTimeRangeOptions: oteleport.ClientTimeRangeOptions{
+					StartTime: &c.startTime,
+					EndTime:   &c.endTime,
+				},
+				ClientTimeShiftOptions: c.opts,
+			})
+			require.NoError(t, err)
+			require.NoError(t, app.Stop(ctx))
+
+			dstClient, err := oteleportclient.New(&oteleportclient.Profile{
+				Endpoint: "http://" + dstCfg.API.HTTP.Address,
+			})
+			require.NoError(t, err)
+			resp, err := dstClient.FetchTracesData(ctx, &oteleportpb.FetchTracesDataRequest{
+				StartTimeUnixNano: uint64(c.fetchFrom.UnixNano()),
+				EndTimeUnixNano:   uint64(c.fetchTo.UnixNano()),
+			})
+			require.NoError(t, err)
+			require.Equal(t, otlp.TotalSpans(traces.GetResourceSpans()), otlp.TotalSpans(resp.GetResourceSpans()))
+			expected := traces.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()[0]
+			actual := resp.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()[0]
+			require.Equal(t,
+				expected.GetEndTimeUnixNano()-expected.GetStartTimeUnixNano(),
+				actual.GetEndTimeUnixNano()-actual.GetStartTimeUnixNano(),
+				"span duration must be preserved",
+			)
+		})
+	}
+
+	app, err := oteleport.NewClientApp(&oteleport.Profile{
+		Profile: &oteleportclient.Profile{
+			Endpoint: "http://" + srcCfg.API.HTTP.Address,
+		},
+	})
+	require.NoError(t, err)
+	err = app.FetchTracesData(ctx, &oteleport.ClientTracesCommandOptions{
+		ClientTimeRangeOptions: oteleport.ClientTimeRangeOptions{
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		},
+		ClientTimeShiftOptions: oteleport.ClientTimeShiftOptions{TimeOffset: -time.Duration(startTime.UnixNano()) - time.Hour},
+	})
+	require.Error(t, err, "offset before the unix epoch is rejected")
+}
+
+func TestClientApp__ExportTraces__Replay(t *testing.T) {
+	ctx := context.Background()
+	srcCfg := startTestMemoryServer(t, ctx)
+	// 5 spans, 1 second apart
+	traces := newTestTracesData(0, 5)
+	otlpClient, err := otlp.NewClient("http://" + srcCfg.OTLP.GRPC.Address)
+	require.NoError(t, err)
+	require.NoError(t, otlpClient.Start(ctx))
+	require.NoError(t, otlpClient.UploadTraces(ctx, traces.GetResourceSpans()))
+	require.NoError(t, otlpClient.Stop(ctx))
+	startTime := testBaseTime.Add(-time.Minute)
+	endTime := testBaseTime.Add(time.Minute)
+
+	cases := []struct {
+		name       string
+		opts       oteleport.ClientReplayOptions
+		minElapsed time.Duration
+	}{
+		{
+			name:       "paced_4x",
+			opts:       oteleport.ClientReplayOptions{Paced: true, Speed: 4},
+			minElapsed: time.Second,
+		},
+		{
+			name:       "max_signals_per_second",
+			opts:       oteleport.ClientReplayOptions{Speed: 1, MaxSignalsPerSecond: 4},
+			minElapsed: time.Second,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dstCfg := startTestMemoryServer(t, ctx)
+			app, err := oteleport.NewClientApp(&oteleport.Profile{
+				Profile: &oteleportclient.Profile{
+					Endpoint: "http://" + srcCfg.API.HTTP.Address,
+				},
+				Output: oteleport.ClientSignalOutputOptions{
+					OtelExporterOTLPEndpoint: "http://" + dstCfg.OTLP.GRPC.Address,
+					OtelExporterOTLPProtocol: "grpc",
+				},
+			})
+			require.NoError(t, err)
+			require.NoError(t, app.Start(ctx))
+			now := time.Now()
+			err = app.FetchTracesData(ctx, &oteleport.ClientTracesCommandOptions{
+				ClientTimeRangeOptions: oteleport.ClientTimeRangeOptions{
+					StartTime: &startTime,
+					EndTime:   &endTime,
+				},
+				ClientReplayOptions: c.opts,
+			})
+			require.NoError(t, err)
+			elapsed := time.Since(now)
+			require.NoError(t, app.Stop(ctx))
+			require.GreaterOrEqual(t, elapsed, c.minElapsed)
+			require.Less(t, elapsed, 4*time.Second)
+
+			dstClient, err := oteleportclient.New(&oteleportclient.Profile{
+				Endpoint: "http://" + dstCfg.API.HTTP.Address,
+			})
+			require.NoError(t, err)
+			resp, err := dstClient.FetchTracesData(ctx, &oteleportpb.FetchTracesDataRequest{
+				StartTimeUnixNano: uint64(startTime.UnixNano()),
+				EndTimeUnixNano:   uint64(endTime.UnixNano()),
+			})
+			require.NoError(t, err)
+			require.Equal(t, 5, otlp.TotalSpans(resp.GetResourceSpans()))
+		})
+	}
+}
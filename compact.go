@@ -0,0 +1,191 @@
+package oteleport
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mashiike/go-otlp-helper/otlp"
+	"github.com/samber/oops"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// defaultCompactMaxBytes is the default max stored size of source objects merged into one object.
+const defaultCompactMaxBytes = 64 * 1024 * 1024
+
+type CompactOptions struct {
+	// Signal is traces, metrics or logs.
+	Signal    string
+	StartTime time.Time
+	EndTime   time.Time
+	// MaxBytes is the max total stored size of source objects merged into one object.
+	MaxBytes int64
+}
+
+type CompactResult struct {
+	SourceObjects    int
+	SourceBytes      int64
+	CompactedObjects int
+}
+
+// Compact merges small objects in each partition between StartTime and EndTime into larger objects.
+// the merged object is written first, and the source objects are deleted only after it is written.
+func (r *ObjectSignalRepository) Compact(ctx context.Context, opts *CompactOptions) (*CompactResult, error) {
+	switch opts.Signal {
+	case "traces", "metrics", "logs":
+	default:
+		return nil, oops.Errorf("unsupported signal %q", opts.Signal)
+	}
+	if opts.StartTime.After(opts.EndTime) {
+		return nil, oops.Errorf("start time is after end time")
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultCompactMaxBytes
+	}
+	// objects are grouped by the directory, so that resource partitions are kept.
+	var dirs []string
+	objectsByDir := make(map[string][]storageObject)
+	_, err := r.listPartitionObjects(ctx, opts.StartTime, opts.EndTime, nil, opts.Signal, "", func(_ context.Context, _ time.Time, obj storageObject) (bool, error) {
+		dir := path.Dir(obj.Key)
+		if _, ok := objectsByDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		objectsByDir[dir] = append(objectsByDir[dir], obj)
+		return true, nil
+	})
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to list objects")
+	}
+	result := &CompactResult{}
+	signalPrefix := r.objectKeyPrefix(opts.Signal + "/")
+	for _, dir := range dirs {
+		partition := strings.TrimPrefix(dir, signalPrefix)
+		for _, chunk := range chunkObjects(objectsByDir[dir], maxBytes) {
+			if len(chunk) < 2 {
+				continue
+			}
+			slog.InfoContext(ctx, "compact objects", "signal", opts.Signal, "partition", partition, "objects", len(chunk))
+			if err := r.compactObjects(ctx, opts.Signal, partition, chunk); err != nil {
+				return result, oops.Wrapf(err, "failed to compact partition %q", partition)
+			}
+			result.CompactedObjects++
+			for _, obj := range chunk {
+				result.SourceObjects++
+				result.SourceBytes += obj.Size
+			}
+		}
+	}
+	return result, nil
+}
+
+// chunkObjects splits objects into chunks, total size of a chunk does not exceed maxBytes unless the chunk has only one object.
+func chunkObjects(objects []storageObject, maxBytes int64) [][]storageObject {
+	chunks := make([][]storageObject, 0)
+	var chunk []storageObject
+	var size int64
+	for _, obj := range objects {
+		if len(chunk) > 0 && size+obj.Size > maxBytes {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 0
+		}
+		chunk = append(chunk, obj)
+		size += obj.Size
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func (r *ObjectSignalRepository) compactObjects(ctx context.Context, signal string, partition string, objects []storageObject) error {
+	switch signal {
+	case "traces":
+		var spans []*tracepb.ResourceSpans
+		for _, obj := range objects {
+			data, err := r.getTracesData(ctx, obj)
+			if err != nil {
+				return err
+			}
+			spans = otlp.AppendResourceSpans(spans, data.GetResourceSpans()...)
+		}
+		if err := r.writeResourceSpans(ctx, partition, spans); err != nil {
+			return err
+		}
+		if err := r.deleteTraceIndexObjects(ctx, partition, objects); err != nil {
+			return err
+		}
+	case "metrics":
+		var metrics []*metricspb.ResourceMetrics
+		for _, obj := range objects {
+			data, err := r.getMetricsData(ctx, obj)
+			if err != nil {
+				return err
+			}
+			metrics = otlp.AppendResourceMetrics(metrics, data.GetResourceMetrics()...)
+		}
+		if err := r.writeResourceMetrics(ctx, partition, metrics); err != nil {
+			return err
+		}
+	case "logs":
+		var logs []*logspb.ResourceLogs
+		for _, obj := range objects {
+			data, err := r.getLogsData(ctx, obj)
+			if err != nil {
+				return err
+			}
+			logs = otlp.AppendResourceLogs(logs, data.GetResourceLogs()...)
+		}
+		if err := r.writeResourceLogs(ctx, partition, logs); err != nil {
+			return err
+		}
+	}
+	for _, obj := range objects {
+		if err := r.storage.DeleteObject(ctx, obj.Key); err != nil {
+			return oops.Wrapf(err, "failed to delete compacted object %q", obj.Key)
+		}
+	}
+	return nil
+}
+
+// deleteTraceIndexObjects deletes the index objects written alongside the span objects.
+// the index object of `spans-<name>.json` is `index-<name>.json`.
+func (r *ObjectSignalRepository) deleteTraceIndexObjects(ctx context.Context, partition string, objects []storageObject) error {
+	names := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		names[objectName(path.Base(obj.Key), "spans-")] = true
+	}
+	indexPrefix := r.objectKeyPrefix("traces-index/" + partition + "/")
+	var keys []string
+	_, err := r.storage.ListObjects(ctx, indexPrefix, nil, func(obj storageObject) (bool, error) {
+		if path.Dir(obj.Key)+"/" != indexPrefix {
+			return true, nil
+		}
+		if names[objectName(path.Base(obj.Key), "index-")] {
+			keys = append(keys, obj.Key)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return oops.Wrapf(err, "failed to list trace index objects")
+	}
+	for _, key := range keys {
+		if err := r.storage.DeleteObject(ctx, key); err != nil {
+			return oops.Wrapf(err, "failed to delete trace index object %q", key)
+		}
+	}
+	return nil
+}
+
+// objectName returns the name part of the object file name, like `20241105130000-abcdefgh` of `spans-20241105130000-abcdefgh.json.gz`.
+func objectName(base string, prefix string) string {
+	name := strings.TrimPrefix(base, prefix)
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,129 @@
+package oteleport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/samber/oops"
+)
+
+const (
+	CompressionGZip   = "gzip"
+	CompressionZstd   = "zstd"
+	CompressionSnappy = "snappy"
+	CompressionNone   = "none"
+)
+
+// compressionCodec is the compression of stored objects.
+// the codec of an object is detected by the key suffix, or by the magic number for objects without the suffix.
+type compressionCodec struct {
+	name            string
+	suffix          string
+	contentEncoding string
+	magic           []byte
+	newWriter       func(io.Writer) (io.WriteCloser, error)
+	newReader       func(io.Reader) (io.Reader, error)
+}
+
+var compressionCodecs = []*compressionCodec{
+	{
+		name:            CompressionGZip,
+		suffix:          ".gz",
+		contentEncoding: "gzip",
+		magic:           []byte{0x1f, 0x8b},
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		},
+		newReader: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+	},
+	{
+		name:            CompressionZstd,
+		suffix:          ".zst",
+		contentEncoding: "zstd",
+		magic:           []byte{0x28, 0xb5, 0x2f, 0xfd},
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		},
+		newReader: func(r io.Reader) (io.Reader, error) {
+			dec, err := zstd.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return dec.IOReadCloser(), nil
+		},
+	},
+	{
+		// snappy framing format, readable by the common snappy tools.
+		name:            CompressionSnappy,
+		suffix:          ".sz",
+		contentEncoding: "x-snappy-framed",
+		magic:           []byte{0xff, 0x06, 0x00, 0x00, 's', 'N', 'a', 'P', 'p', 'Y'},
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return s2.NewWriter(w, s2.WriterSnappyCompat()), nil
+		},
+		newReader: func(r io.Reader) (io.Reader, error) {
+			return s2.NewReader(r), nil
+		},
+	},
+}
+
+// compressionCodecByName returns nil for none.
+func compressionCodecByName(name string) *compressionCodec {
+	for _, c := range compressionCodecs {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// detectCompressionCodec returns the codec of the object, nil means not compressed.
+func detectCompressionCodec(key string, body []byte) *compressionCodec {
+	for _, c := range compressionCodecs {
+		if strings.HasSuffix(key, c.suffix) {
+			return c
+		}
+	}
+	for _, c := range compressionCodecs {
+		if bytes.HasPrefix(body, c.magic) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (c *compressionCodec) compress(body io.Reader) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	w, err := c.newWriter(&buf)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to create %s writer", c.name)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		return nil, oops.Wrapf(err, "failed to write %s", c.name)
+	}
+	if err := w.Close(); err != nil {
+		return nil, oops.Wrapf(err, "failed to close %s", c.name)
+	}
+	return &buf, nil
+}
+
+func (c *compressionCodec) decompress(body []byte) ([]byte, error) {
+	r, err := c.newReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to create %s reader", c.name)
+	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to read %s", c.name)
+	}
+	return decompressed, nil
+}
@@ -2,11 +2,16 @@ package oteleport
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -18,23 +23,168 @@ import (
 type ServerConfig struct {
 	AccessKeyHeader string             `json:"access_key_header"`
 	AccessKeys      []*AccessKeyConfig `json:"access_keys"`
+	Auth            AuthConfig         `json:"auth,omitempty"`
 	Storage         StorageConfig      `json:"storage"`
 	OTLP            OTLPConfig         `json:"otlp"`
 	API             APIConfig          `json:"api"`
 }
 
+// AccessKeyConfig is an access key of OTLP and API endpoints.
+// SecretKeyHash is the hash of the secret key by `oteleport-server hash-key`, used instead of SecretKey.
+// Tenant isolates signals of the access key, signals are stored under `tenants/<tenant>/`.
+// ClientCertSubject authenticates requests with a verified client certificate of the subject, the common name or the distinguished name.
 type AccessKeyConfig struct {
-	KeyID     string `json:"key_id"`
-	SecretKey string `json:"secret_key"`
+	KeyID             string   `json:"key_id"`
+	SecretKey         string   `json:"secret_key,omitempty"`
+	SecretKeyHash     string   `json:"secret_key_hash,omitempty"`
+	ClientCertSubject string   `json:"client_cert_subject,omitempty"`
+	Scopes            []string `json:"scopes,omitempty"`
+	Tenant            string   `json:"tenant,omitempty"`
+}
+
+type AuthConfig struct {
+	JWT AuthJWTConfig `json:"jwt,omitempty"`
+}
+
+// AuthJWTConfig is the authentication by `Authorization: Bearer` tokens, enabled when JWKS is set.
+// claims of the token are mapped to an access key, with scopes and the tenant.
+type AuthJWTConfig struct {
+	// JWKS is a file path or a http(s) URL of the JSON Web Key Set.
+	JWKS         string `json:"jwks,omitempty"`
+	JWKSCacheTTL string `json:"jwks_cache_ttl,omitempty"`
+	Issuer       string `json:"issuer,omitempty"`
+	Audience     string `json:"audience,omitempty"`
+	// KeyIDClaim is the claim of the key id of the access key, default is `sub`.
+	KeyIDClaim string `json:"key_id_claim,omitempty"`
+	// ScopesClaim is the claim of scopes, a space separated string or an array, default is `scope`.
+	ScopesClaim string `json:"scopes_claim,omitempty"`
+	// ScopeMapping maps values of the scopes claim to scopes, known scopes in the claim are mapped as is.
+	ScopeMapping map[string][]string `json:"scope_mapping,omitempty"`
+	// TenantClaim is the claim of the tenant, no tenant when empty.
+	// when empty, every valid token reads and writes the top-level (tenant-less) data, set it on shared deployments.
+	TenantClaim  string        `json:"tenant_claim,omitempty"`
+	jwksCacheTTL time.Duration `json:"-"`
+}
+
+func (c *AuthJWTConfig) Enabled() bool {
+	return c.JWKS != ""
+}
+
+func (c *AuthJWTConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Issuer == "" {
+		return oops.Errorf("issuer is required")
+	}
+	if c.Audience == "" {
+		return oops.Errorf("audience is required")
+	}
+	if c.KeyIDClaim == "" {
+		c.KeyIDClaim = "sub"
+	}
+	if c.ScopesClaim == "" {
+		c.ScopesClaim = "scope"
+	}
+	for value, scopes := range c.ScopeMapping {
+		for _, scope := range scopes {
+			if !slices.Contains(knownScopes, scope) {
+				return oops.Errorf("scope_mapping %s has unknown scope %s", value, scope)
+			}
+		}
+	}
+	if c.JWKSCacheTTL == "" {
+		c.JWKSCacheTTL = "1h"
+	}
+	d, err := time.ParseDuration(c.JWKSCacheTTL)
+	if err != nil {
+		return oops.Wrapf(err, "failed to parse jwks_cache_ttl")
+	}
+	if d < 0 {
+		return oops.Errorf("jwks_cache_ttl must not be negative")
+	}
+	c.jwksCacheTTL = d
+	return nil
 }
 
 type StorageConfig struct {
-	CursorEncryptionKey []byte           `json:"cursor_encryption_key"`
-	GZip                *bool            `json:"gzip,omitempty"`
-	Flatten             *bool            `json:"flatten,omitempty"`
-	Location            string           `json:"location"`
-	locationURL         *url.URL         `json:"-"`
-	AWS                 StorageAWSConfig `json:"aws,omitempty"`
+	CursorEncryptionKey []byte                 `json:"cursor_encryption_key"`
+	GZip                *bool                  `json:"gzip,omitempty"`
+	Compression         string                 `json:"compression,omitempty"`
+	Flatten             *bool                  `json:"flatten,omitempty"`
+	Format              string                 `json:"format,omitempty"`
+	Partition           StoragePartitionConfig `json:"partition,omitempty"`
+	PartitionStyle      string                 `json:"partition_style,omitempty"`
+	Batch               StorageBatchConfig     `json:"batch,omitempty"`
+	Retention           StorageRetentionConfig `json:"retention,omitempty"`
+	PrefetchWorkers     int                    `json:"prefetch_workers,omitempty"`
+	Manifest            StorageManifestConfig  `json:"manifest,omitempty"`
+	Location            string                 `json:"location"`
+	locationURL         *url.URL               `json:"-"`
+	AWS                 StorageAWSConfig       `json:"aws,omitempty"`
+	Memory              StorageMemoryConfig    `json:"memory,omitempty"`
+}
+
+const (
+	StorageFormatJSON     = "json"
+	StorageFormatParquet  = "parquet"
+	StorageFormatProtobuf = "protobuf"
+)
+
+const (
+	PartitionGranularityMinute = "minute"
+	PartitionGranularityHour   = "hour"
+	PartitionGranularityDay    = "day"
+)
+
+const (
+	PartitionStyleDefault = "default"
+	PartitionStyleHive    = "hive"
+)
+
+// StoragePartitionConfig is the partition layout of object keys.
+type StoragePartitionConfig struct {
+	Granularity string `json:"granularity,omitempty"`
+	// Timezone of partitions, defaults to UTC.
+	// older versions wrote partitions in the server local timezone,
+	// set `Local` to keep reading data written by them.
+	Timezone          string         `json:"timezone,omitempty"`
+	ResourceAttribute string         `json:"resource_attribute,omitempty"`
+	location          *time.Location `json:"-"`
+}
+
+// StorageBatchConfig is the in-process write buffer of signals.
+// buffered signals are written when any of the limits is reached.
+type StorageBatchConfig struct {
+	Enable         *bool         `json:"enable,omitempty"`
+	MaxBytes       int           `json:"max_bytes,omitempty"`
+	MaxRecords     int           `json:"max_records,omitempty"`
+	MaxAge         string        `json:"max_age,omitempty"`
+	MaxBufferBytes int           `json:"max_buffer_bytes,omitempty"`
+	maxAge         time.Duration `json:"-"`
+}
+
+// StorageRetentionConfig is the retention period of stored signals per signal.
+// partitions older than the retention are deleted by purge.
+type StorageRetentionConfig struct {
+	Traces        string        `json:"traces,omitempty"`
+	Metrics       string        `json:"metrics,omitempty"`
+	Logs          string        `json:"logs,omitempty"`
+	PurgeInterval string        `json:"purge_interval,omitempty"`
+	traces        time.Duration `json:"-"`
+	metrics       time.Duration `json:"-"`
+	logs          time.Duration `json:"-"`
+	purgeInterval time.Duration `json:"-"`
+}
+
+// StorageManifestConfig is the manifest of non-empty partitions.
+type StorageManifestConfig struct {
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type StorageMemoryConfig struct {
+	MaxSignals int64 `json:"max_signals"`
+	MaxBytes   int64 `json:"max_bytes"`
 }
 
 type StorageAWSConfig struct {
@@ -62,6 +212,7 @@ type OTLPConfig struct {
 type OTLPGRPCConfig struct {
 	Enable   *bool        `json:"enable,omitempty"`
 	Address  string       `json:"address"`
+	TLS      TLSConfig    `json:"tls,omitempty"`
 	Listener net.Listener `json:"-"`
 }
 
@@ -70,6 +221,7 @@ type OTLPHTTPConfig struct {
 	Enable   *bool        `json:"enable,omitempty"`
 	Prefix   string       `json:"prefix"`
 	Address  string       `json:"address"`
+	TLS      TLSConfig    `json:"tls,omitempty"`
 	Listener net.Listener `json:"-"`
 }
 
@@ -85,15 +237,74 @@ type APIHTTPConfig struct {
 	Enable   *bool        `json:"enable,omitempty"`
 	Prefix   string       `json:"prefix"`
 	Address  string       `json:"address"`
+	TLS      TLSConfig    `json:"tls,omitempty"`
 	Listener net.Listener `json:"-"`
 }
 
 type APIGRPCConfig struct {
 	Enable   *bool        `json:"enable,omitempty"`
 	Address  string       `json:"address"`
+	TLS      TLSConfig    `json:"tls,omitempty"`
 	Listener net.Listener `json:"-"`
 }
 
+// TLSConfig is the TLS of a listener, enabled when CertFile is set.
+// certificates and the client CA are loaded again when the files are changed.
+type TLSConfig struct {
+	CertFile     string `json:"cert_file,omitempty"`
+	KeyFile      string `json:"key_file,omitempty"`
+	ClientCAFile string `json:"client_ca_file,omitempty"`
+	// ClientAuth is `verify_if_given` (default) or `require`, only used with ClientCAFile.
+	ClientAuth string `json:"client_auth,omitempty"`
+	// MinVersion is `1.2` (default) or `1.3`.
+	MinVersion string             `json:"min_version,omitempty"`
+	minVersion uint16             `json:"-"`
+	clientAuth tls.ClientAuthType `json:"-"`
+}
+
+const (
+	TLSClientAuthVerifyIfGiven = "verify_if_given"
+	TLSClientAuthRequire       = "require"
+)
+
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+func (c *TLSConfig) Validate() error {
+	if !c.Enabled() {
+		if c.KeyFile != "" || c.ClientCAFile != "" {
+			return oops.Errorf("cert_file is required")
+		}
+		return nil
+	}
+	if c.KeyFile == "" {
+		return oops.Errorf("key_file is required")
+	}
+	switch c.MinVersion {
+	case "", "1.2":
+		c.minVersion = tls.VersionTLS12
+	case "1.3":
+		c.minVersion = tls.VersionTLS13
+	default:
+		return oops.Errorf("unsupported min_version %s, must be 1.2 or 1.3", c.MinVersion)
+	}
+	c.clientAuth = tls.NoClientCert
+	if c.ClientCAFile != "" {
+		switch c.ClientAuth {
+		case "", TLSClientAuthVerifyIfGiven:
+			c.clientAuth = tls.VerifyClientCertIfGiven
+		case TLSClientAuthRequire:
+			c.clientAuth = tls.RequireAndVerifyClientCert
+		default:
+			return oops.Errorf("unsupported client_auth %s", c.ClientAuth)
+		}
+	} else if c.ClientAuth != "" {
+		return oops.Errorf("client_ca_file is required for client_auth")
+	}
+	return nil
+}
+
 func Pointer[T any](v T) *T {
 	return &v
 }
@@ -122,12 +333,15 @@ func DefaultServerConfig() *ServerConfig {
 			HTTP: APIHTTPConfig{
 				Address: ":8080",
 			},
+			GRPC: APIGRPCConfig{
+				Address: ":8081",
+			},
 		},
 	}
 }
 
 func (c *ServerConfig) EnableAuth() bool {
-	return len(c.AccessKeys) > 0
+	return len(c.AccessKeys) > 0 || c.Auth.JWT.Enabled()
 }
 
 // Validate function to check the configuration for validity
@@ -141,6 +355,9 @@ func (c *ServerConfig) Validate() error {
 	if err := c.API.Validate(); err != nil {
 		return oops.Wrapf(err, "api")
 	}
+	if err := c.Auth.JWT.Validate(); err != nil {
+		return oops.Wrapf(err, "auth.jwt")
+	}
 	keyIDs := make(map[string]int)
 	for index, keyCfg := range c.AccessKeys {
 		if keyCfg.KeyID == "" {
@@ -150,9 +367,27 @@ func (c *ServerConfig) Validate() error {
 			return oops.Errorf("duplicate access key id: index %d and %d", duplicateIndex, index)
 		}
 		keyIDs[keyCfg.KeyID] = index
-		if keyCfg.SecretKey == "" {
+		if keyCfg.SecretKey == "" && keyCfg.SecretKeyHash == "" && keyCfg.ClientCertSubject == "" {
 			return oops.Errorf("access secret key index=%d is empty", index)
 		}
+		if keyCfg.SecretKey != "" && keyCfg.SecretKeyHash != "" {
+			return oops.Errorf("access key index=%d has both secret_key and secret_key_hash", index)
+		}
+		if keyCfg.SecretKeyHash != "" {
+			if _, _, err := parseSecretKeyHash(keyCfg.SecretKeyHash); err != nil {
+				return oops.Wrapf(err, "access key index=%d", index)
+			}
+		}
+		for _, scope := range keyCfg.Scopes {
+			if !slices.Contains(knownScopes, scope) {
+				return oops.Errorf("access key index=%d has unknown scope %s", index, scope)
+			}
+		}
+		if keyCfg.Tenant != "" {
+			if !tenantPattern.MatchString(keyCfg.Tenant) {
+				return oops.Errorf("access key index=%d has invalid tenant %q, allowed characters are [a-zA-Z0-9_-]", index, keyCfg.Tenant)
+			}
+		}
 	}
 	return nil
 }
@@ -194,9 +429,67 @@ func (c *StorageConfig) Validate(parent *ServerConfig) error {
 	if c.GZip == nil {
 		c.GZip = Coalasce(parent.Storage.GZip, Pointer(true))
 	}
+	if c.Compression == "" {
+		c.Compression = parent.Storage.Compression
+	}
+	if c.Compression == "" {
+		// gzip is kept for compatibility, compression takes precedence.
+		c.Compression = CompressionNone
+		if *c.GZip {
+			c.Compression = CompressionGZip
+		}
+	}
+	switch c.Compression {
+	case CompressionGZip, CompressionZstd, CompressionSnappy, CompressionNone:
+	default:
+		return oops.Errorf("unsupported compression %s", c.Compression)
+	}
+	if c.Format == "" {
+		c.Format = parent.Storage.Format
+	}
+	if c.Format == "" {
+		c.Format = StorageFormatJSON
+	}
+	switch c.Format {
+	case StorageFormatJSON, StorageFormatProtobuf:
+	case StorageFormatParquet:
+		// parquet rows are always flattened signals.
+		if c.Flatten != nil && !*c.Flatten {
+			return oops.Errorf("format parquet requires flatten")
+		}
+		c.Flatten = Pointer(true)
+	default:
+		return oops.Errorf("unsupported format %s", c.Format)
+	}
 	if c.Flatten == nil {
 		c.Flatten = Coalasce(parent.Storage.Flatten, Pointer(false))
 	}
+	if err := c.Partition.Validate(); err != nil {
+		return oops.Wrapf(err, "partition")
+	}
+	if c.PartitionStyle == "" {
+		c.PartitionStyle = PartitionStyleDefault
+	}
+	switch c.PartitionStyle {
+	case PartitionStyleDefault, PartitionStyleHive:
+	default:
+		return oops.Errorf("unsupported partition_style %s", c.PartitionStyle)
+	}
+	if err := c.Batch.Validate(); err != nil {
+		return oops.Wrapf(err, "batch")
+	}
+	if err := c.Retention.Validate(); err != nil {
+		return oops.Wrapf(err, "retention")
+	}
+	if c.PrefetchWorkers < 0 {
+		return oops.Errorf("prefetch_workers must be positive")
+	}
+	if c.PrefetchWorkers == 0 {
+		c.PrefetchWorkers = 4
+	}
+	if c.Manifest.Enable == nil {
+		c.Manifest.Enable = Pointer(false)
+	}
 	if c.Location == "" {
 		return oops.Errorf("location is required")
 	}
@@ -212,6 +505,18 @@ func (c *StorageConfig) Validate(parent *ServerConfig) error {
 		if err := c.AWS.Validate(); err != nil {
 			return oops.Wrapf(err, "aws")
 		}
+	case "file":
+		if u.Host+u.Path == "" {
+			return oops.Errorf("file directory path is required")
+		}
+	case "memory":
+		if err := c.Memory.Validate(); err != nil {
+			return oops.Wrapf(err, "memory")
+		}
+		// markers may be evicted before the objects of the partition.
+		if *c.Manifest.Enable {
+			return oops.Errorf("manifest is not supported on memory location")
+		}
 	default:
 		return oops.Errorf("unsupported location scheme %s", u.Scheme)
 	}
@@ -219,6 +524,26 @@ func (c *StorageConfig) Validate(parent *ServerConfig) error {
 	return nil
 }
 
+func (c *StoragePartitionConfig) Validate() error {
+	if c.Granularity == "" {
+		c.Granularity = PartitionGranularityHour
+	}
+	switch c.Granularity {
+	case PartitionGranularityMinute, PartitionGranularityHour, PartitionGranularityDay:
+	default:
+		return oops.Errorf("unsupported granularity %s", c.Granularity)
+	}
+	if c.Timezone == "" {
+		c.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return oops.Wrapf(err, "failed to load timezone %s", c.Timezone)
+	}
+	c.location = loc
+	return nil
+}
+
 func (c *StorageAWSConfig) Validate() error {
 	loadConfigOptions := []func(*config.LoadOptions) error{}
 	if c.Credentials != nil {
@@ -237,6 +562,106 @@ func (c *StorageAWSConfig) Validate() error {
 	return nil
 }
 
+func (c *StorageBatchConfig) Validate() error {
+	if c.Enable == nil {
+		c.Enable = Pointer(false)
+	}
+	if c.MaxBytes < 0 {
+		return oops.Errorf("max_bytes must be positive")
+	}
+	if c.MaxRecords < 0 {
+		return oops.Errorf("max_records must be positive")
+	}
+	if c.MaxBytes == 0 {
+		c.MaxBytes = 8 * 1024 * 1024
+	}
+	if c.MaxRecords == 0 {
+		c.MaxRecords = 10000
+	}
+	if c.MaxBufferBytes < 0 {
+		return oops.Errorf("max_buffer_bytes must be positive")
+	}
+	if c.MaxBufferBytes == 0 {
+		c.MaxBufferBytes = max(8*c.MaxBytes, 64*1024*1024)
+	}
+	if c.MaxBufferBytes < c.MaxBytes {
+		return oops.Errorf("max_buffer_bytes must not be less than max_bytes")
+	}
+	if c.MaxAge == "" {
+		c.MaxAge = "1m"
+	}
+	d, err := time.ParseDuration(c.MaxAge)
+	if err != nil {
+		return oops.Wrapf(err, "failed to parse max_age")
+	}
+	if d <= 0 {
+		return oops.Errorf("max_age must be positive")
+	}
+	c.maxAge = d
+	return nil
+}
+
+func (c *StorageRetentionConfig) Validate() error {
+	var err error
+	if c.traces, err = parseRetention(c.Traces); err != nil {
+		return oops.Wrapf(err, "traces")
+	}
+	if c.metrics, err = parseRetention(c.Metrics); err != nil {
+		return oops.Wrapf(err, "metrics")
+	}
+	if c.logs, err = parseRetention(c.Logs); err != nil {
+		return oops.Wrapf(err, "logs")
+	}
+	if c.PurgeInterval != "" {
+		d, err := time.ParseDuration(c.PurgeInterval)
+		if err != nil {
+			return oops.Wrapf(err, "failed to parse purge_interval")
+		}
+		if d <= 0 {
+			return oops.Errorf("purge_interval must be positive")
+		}
+		c.purgeInterval = d
+	}
+	return nil
+}
+
+// parseRetention parses the retention period like `14d` or `36h`, empty string means no retention.
+func parseRetention(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, oops.Wrapf(err, "failed to parse retention %s", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, oops.Wrapf(err, "failed to parse retention %s", s)
+		}
+	}
+	if d <= 0 {
+		return 0, oops.Errorf("retention must be positive")
+	}
+	return d, nil
+}
+
+func (c *StorageMemoryConfig) Validate() error {
+	if c.MaxSignals < 0 {
+		return oops.Errorf("max_signals must be positive")
+	}
+	if c.MaxBytes < 0 {
+		return oops.Errorf("max_bytes must be positive")
+	}
+	if c.MaxSignals == 0 && c.MaxBytes == 0 {
+		c.MaxSignals = 100000
+	}
+	return nil
+}
+
 func (c *OTLPConfig) Validate() error {
 	if err := c.GRPC.Validate(c); err != nil {
 		return oops.Wrapf(err, "grpc")
@@ -257,6 +682,9 @@ func (c *OTLPGRPCConfig) Validate(parent *OTLPConfig) error {
 	if *c.Enable && c.Address == "" {
 		return oops.Errorf("address is required")
 	}
+	if err := c.TLS.Validate(); err != nil {
+		return oops.Wrapf(err, "tls")
+	}
 	return nil
 }
 
@@ -270,6 +698,9 @@ func (c *OTLPHTTPConfig) Validate(parent *OTLPConfig) error {
 	if *c.Enable && c.Address == "" {
 		return oops.Errorf("address is required")
 	}
+	if err := c.TLS.Validate(); err != nil {
+		return oops.Wrapf(err, "tls")
+	}
 	return nil
 }
 
@@ -277,6 +708,9 @@ func (c *APIConfig) Validate() error {
 	if err := c.HTTP.Validate(c); err != nil {
 		return oops.Wrapf(err, "http")
 	}
+	if err := c.GRPC.Validate(c); err != nil {
+		return oops.Wrapf(err, "grpc")
+	}
 	return nil
 }
 
@@ -290,6 +724,26 @@ func (c *APIHTTPConfig) Validate(parent *APIConfig) error {
 	if *c.Enable && c.Address == "" {
 		return oops.Errorf("address is required")
 	}
+	if err := c.TLS.Validate(); err != nil {
+		return oops.Wrapf(err, "tls")
+	}
+	return nil
+}
+
+// Validate of the gRPC API does not follow `api.enable`, the gRPC API is opt-in not to open a new listener on upgrade.
+func (c *APIGRPCConfig) Validate(_ *APIConfig) error {
+	if c.Enable == nil {
+		c.Enable = Pointer(false)
+	}
+	if c.Listener != nil {
+		c.Address = c.Listener.Addr().String()
+	}
+	if *c.Enable && c.Address == "" {
+		return oops.Errorf("address is required")
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return oops.Wrapf(err, "tls")
+	}
 	return nil
 }
 
@@ -52,3 +52,16 @@ func TestServerConfig_Load(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIConfig_Validate__GRPCOptIn(t *testing.T) {
+	cfg := oteleport.DefaultServerConfig()
+	cfg.API.Enable = oteleport.Pointer(true)
+	assert.NoError(t, cfg.API.Validate())
+	assert.True(t, *cfg.API.HTTP.Enable)
+	assert.False(t, *cfg.API.GRPC.Enable, "api.enable does not enable the gRPC API")
+
+	cfg = oteleport.DefaultServerConfig()
+	cfg.API.GRPC.Enable = oteleport.Pointer(true)
+	assert.NoError(t, cfg.API.Validate())
+	assert.True(t, *cfg.API.GRPC.Enable)
+}
@@ -0,0 +1,72 @@
+package oteleport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// failingObjectStorage fails PutObject while failing is true.
+type failingObjectStorage struct {
+	objectStorage
+	failing *atomic.Bool
+}
+
+func (s *failingObjectStorage) PutObject(ctx context.Context, key string, body io.Reader, opts *putObjectOptions) error {
+	if s.failing.Load() {
+		return errors.New("storage is unavailable")
+	}
+	return s.objectStorage.PutObject(ctx, key, body, opts)
+}
+
+// NewFailingSignalRepository returns the repository over the memory storage, writes fail while failing is true.
+func NewFailingSignalRepository(cfg *StorageConfig, failing *atomic.Bool) SignalRepository {
+	return newObjectSignalRepository(cfg, &failingObjectStorage{
+		objectStorage: newMemoryObjectStorage(cfg),
+		failing:       failing,
+	}, "", newBufferBudget(cfg.Batch.MaxBufferBytes))
+}
+
+// listRecordingObjectStorage calls listed with each object key listed.
+type listRecordingObjectStorage struct {
+	objectStorage
+	listed func(key string)
+}
+
+func (s *listRecordingObjectStorage) ListObjects(ctx context.Context, prefix string, startAfter *string, f func(storageObject) (bool, error)) (bool, error) {
+	return s.objectStorage.ListObjects(ctx, prefix, startAfter, func(obj storageObject) (bool, error) {
+		s.listed(obj.Key)
+		return f(obj)
+	})
+}
+
+// NewListRecordingSignalRepository returns the repository over the file storage, listed is called with each object key listed.
+func NewListRecordingSignalRepository(cfg *StorageConfig, listed func(key string)) SignalRepository {
+	return newObjectSignalRepository(cfg, &listRecordingObjectStorage{
+		objectStorage: newFileObjectStorage(cfg),
+		listed:        listed,
+	}, "", newBufferBudget(cfg.Batch.MaxBufferBytes))
+}
+
+// NewTLSReloader returns the reloader of the certificate files, the files are loaded.
+func NewTLSReloader(cfg *TLSConfig) (*tlsReloader, error) {
+	r := &tlsReloader{cfg: cfg, modTimes: make(map[string]time.Time)}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CheckDue reports whether the files are checked at the handshake.
+func (r *tlsReloader) CheckDue() bool {
+	return r.checkDue()
+}
+
+// Reload loads the files if they are changed, as a handshake does.
+func (r *tlsReloader) Reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reload()
+}
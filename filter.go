@@ -0,0 +1,140 @@
+package oteleport
+
+import (
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+
+	oteleportpb "github.com/mashiike/oteleport/proto"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func spanFilter(f *oteleportpb.TracesFilter) func(*resourcepb.Resource, *commonpb.InstrumentationScope, *tracepb.Span) bool {
+	traceIDs := make(map[string]bool, len(f.GetTraceIds()))
+	for _, traceID := range f.GetTraceIds() {
+		traceIDs[strings.ToLower(traceID)] = true
+	}
+	return func(resource *resourcepb.Resource, _ *commonpb.InstrumentationScope, span *tracepb.Span) bool {
+		if f == nil {
+			return true
+		}
+		if len(traceIDs) > 0 && !traceIDs[hex.EncodeToString(span.GetTraceId())] {
+			return false
+		}
+		if !matchAttributes(resource.GetAttributes(), f.GetResourceAttributes()) {
+			return false
+		}
+		if len(f.GetSpanNames()) > 0 && !slices.Contains(f.GetSpanNames(), span.GetName()) {
+			return false
+		}
+		return matchAttributes(span.GetAttributes(), f.GetAttributes())
+	}
+}
+
+// metricFilter returns a filter function for metrics.
+// the metric is expected to be split into one data point, like otlp.FilterResourceMetrics does.
+func metricFilter(f *oteleportpb.MetricsFilter) func(*resourcepb.Resource, *commonpb.InstrumentationScope, *metricspb.Metric) bool {
+	return func(resource *resourcepb.Resource, _ *commonpb.InstrumentationScope, metric *metricspb.Metric) bool {
+		if f == nil {
+			return true
+		}
+		if !matchAttributes(resource.GetAttributes(), f.GetResourceAttributes()) {
+			return false
+		}
+		if len(f.GetMetricNames()) > 0 && !slices.Contains(f.GetMetricNames(), metric.GetName()) {
+			return false
+		}
+		if len(f.GetAttributes()) == 0 {
+			return true
+		}
+		for _, attrs := range dataPointAttributes(metric) {
+			if matchAttributes(attrs, f.GetAttributes()) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func logRecordFilter(f *oteleportpb.LogsFilter) func(*resourcepb.Resource, *commonpb.InstrumentationScope, *logspb.LogRecord) bool {
+	return func(resource *resourcepb.Resource, _ *commonpb.InstrumentationScope, logRecord *logspb.LogRecord) bool {
+		if f == nil {
+			return true
+		}
+		if !matchAttributes(resource.GetAttributes(), f.GetResourceAttributes()) {
+			return false
+		}
+		if logRecord.GetSeverityNumber() < f.GetMinSeverityNumber() {
+			return false
+		}
+		return matchAttributes(logRecord.GetAttributes(), f.GetAttributes())
+	}
+}
+
+func dataPointAttributes(metric *metricspb.Metric) [][]*commonpb.KeyValue {
+	var attrs [][]*commonpb.KeyValue
+	switch data := metric.GetData().(type) {
+	case *metricspb.Metric_Gauge:
+		for _, dp := range data.Gauge.GetDataPoints() {
+			attrs = append(attrs, dp.GetAttributes())
+		}
+	case *metricspb.Metric_Sum:
+		for _, dp := range data.Sum.GetDataPoints() {
+			attrs = append(attrs, dp.GetAttributes())
+		}
+	case *metricspb.Metric_Histogram:
+		for _, dp := range data.Histogram.GetDataPoints() {
+			attrs = append(attrs, dp.GetAttributes())
+		}
+	case *metricspb.Metric_ExponentialHistogram:
+		for _, dp := range data.ExponentialHistogram.GetDataPoints() {
+			attrs = append(attrs, dp.GetAttributes())
+		}
+	case *metricspb.Metric_Summary:
+		for _, dp := range data.Summary.GetDataPoints() {
+			attrs = append(attrs, dp.GetAttributes())
+		}
+	}
+	return attrs
+}
+
+func matchAttributes(attrs []*commonpb.KeyValue, matchers []*oteleportpb.AttributeMatcher) bool {
+	for _, m := range matchers {
+		value, ok := lookupAttribute(attrs, m.GetKey())
+		if !ok || value != m.GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupAttribute(attrs []*commonpb.KeyValue, key string) (string, bool) {
+	for _, kv := range attrs {
+		if kv.GetKey() == key {
+			return anyValueString(kv.GetValue()), true
+		}
+	}
+	return "", false
+}
+
+func anyValueString(v *commonpb.AnyValue) string {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return value.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(value.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(value.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(value.DoubleValue, 'f', -1, 64)
+	case *commonpb.AnyValue_BytesValue:
+		return string(value.BytesValue)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,308 @@
+package oteleport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/samber/oops"
+)
+
+// jwksRefreshInterval is the min interval to fetch the JWKS again for an unknown key id, like after key rotation.
+const jwksRefreshInterval = time.Minute
+
+var jwtValidMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// jwtVerifier verifies bearer tokens by the JWKS, and maps claims to an access key.
+type jwtVerifier struct {
+	cfg        *AuthJWTConfig
+	httpClient *http.Client
+	parser     *jwt.Parser
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+	// loading is closed when the running load of the JWKS is done, nil while not loading.
+	loading chan struct{}
+	loadErr error
+}
+
+func newJWTVerifier(cfg *AuthJWTConfig) *jwtVerifier {
+	return &jwtVerifier{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(jwtValidMethods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+		),
+	}
+}
+
+// verify validates the token, and returns the access key of the claims.
+func (v *jwtVerifier) verify(ctx context.Context, tokenString string) (*AccessKeyConfig, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.lookupKey(ctx, kid)
+	}); err != nil {
+		return nil, oops.Wrapf(err, "invalid token")
+	}
+	key := &AccessKeyConfig{}
+	key.KeyID, _ = claims[v.cfg.KeyIDClaim].(string)
+	if key.KeyID == "" {
+		return nil, oops.Errorf("token has no %s claim", v.cfg.KeyIDClaim)
+	}
+	for _, value := range claimStrings(claims[v.cfg.ScopesClaim]) {
+		if mapped, ok := v.cfg.ScopeMapping[value]; ok {
+			key.Scopes = append(key.Scopes, mapped...)
+		} else if slices.Contains(knownScopes, value) {
+			key.Scopes = append(key.Scopes, value)
+		}
+	}
+	if len(key.Scopes) == 0 {
+		// an access key without scopes is granted all scopes, so a token without scopes is rejected.
+		return nil, oops.Errorf("token has no scopes")
+	}
+	if v.cfg.TenantClaim != "" {
+		key.Tenant, _ = claims[v.cfg.TenantClaim].(string)
+		if key.Tenant == "" || !tenantPattern.MatchString(key.Tenant) {
+			return nil, oops.Errorf("token has no valid %s claim", v.cfg.TenantClaim)
+		}
+	}
+	return key, nil
+}
+
+// claimStrings returns values of a space separated string claim, or an array claim.
+func claimStrings(claim any) []string {
+	switch c := claim.(type) {
+	case string:
+		return strings.Fields(c)
+	case []any:
+		values := make([]string, 0, len(c))
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// lookupKey returns the verification key of the key id, all keys when the token has no key id.
+func (v *jwtVerifier) lookupKey(ctx context.Context, kid string) (any, error) {
+	keys, err := v.currentKeys(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if kid == "" {
+		set := jwt.VerificationKeySet{}
+		for _, key := range keys {
+			set.Keys = append(set.Keys, key)
+		}
+		return set, nil
+	}
+	key, ok := keys[kid]
+	if !ok {
+		return nil, oops.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// currentKeys returns the keys, and loads the JWKS when the keys are expired or the key id is unknown.
+// the JWKS is loaded without the lock by one goroutine at a time, and the cached keys are used while loading.
+// only requests without usable cached keys, like of a new key id after key rotation, wait for the load.
+func (v *jwtVerifier) currentKeys(ctx context.Context, kid string) (map[string]any, error) {
+	v.mu.Lock()
+	keys := v.keys
+	_, known := keys[kid]
+	expired := keys == nil || (v.cfg.jwksCacheTTL > 0 && time.Since(v.fetchedAt) > v.cfg.jwksCacheTTL)
+	refresh := kid != "" && !known && time.Since(v.fetchedAt) > jwksRefreshInterval
+	if !expired && !refresh {
+		v.mu.Unlock()
+		return keys, nil
+	}
+	loading := v.loading
+	if loading == nil {
+		loading = make(chan struct{})
+		v.loading = loading
+		// the load is shared by requests, so that it is not canceled by the request which started it.
+		go v.load(context.WithoutCancel(ctx), loading)
+	}
+	v.mu.Unlock()
+	if keys != nil && !refresh {
+		return keys, nil
+	}
+	select {
+	case <-loading:
+	case <-ctx.Done():
+		return nil, oops.Wrapf(ctx.Err(), "failed to wait for loading jwks")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.keys == nil {
+		return nil, v.loadErr
+	}
+	return v.keys, nil
+}
+
+func (v *jwtVerifier) load(ctx context.Context, done chan struct{}) {
+	keys, err := v.loadKeys(ctx)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	defer close(done)
+	if err != nil {
+		if v.keys != nil {
+			// the cached keys are used until the JWKS is loaded again.
+			slog.WarnContext(ctx, "failed to load jwks, use cached keys", "error", err.Error())
+		}
+	} else {
+		v.keys = keys
+	}
+	v.loadErr = err
+	v.fetchedAt = time.Now()
+	v.loading = nil
+}
+
+func (v *jwtVerifier) loadKeys(ctx context.Context) (map[string]any, error) {
+	var data []byte
+	if strings.HasPrefix(v.cfg.JWKS, "https://") || strings.HasPrefix(v.cfg.JWKS, "http://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKS, nil)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to create jwks request")
+		}
+		resp, err := v.httpClient.Do(req)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to fetch jwks")
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, oops.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+		}
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return nil, oops.Wrapf(err, "failed to read jwks")
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(v.cfg.JWKS); err != nil {
+			return nil, oops.Wrapf(err, "failed to read jwks")
+		}
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "load jwks", "jwks", v.cfg.JWKS, "keys", len(keys))
+	return keys, nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns public keys of the JWKS by the key id, keys not for signatures are skipped.
+func parseJWKS(data []byte) (map[string]any, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, oops.Wrapf(err, "failed to parse jwks")
+	}
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, oops.Wrapf(err, "invalid key %q", jwk.Kid)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (any, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, oops.Wrapf(err, "invalid n")
+		}
+		e, err := decode(jwk.E)
+		if err != nil {
+			return nil, oops.Wrapf(err, "invalid e")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, oops.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, oops.Wrapf(err, "invalid x")
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, oops.Wrapf(err, "invalid y")
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, oops.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, oops.Wrapf(err, "invalid x")
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, oops.Errorf("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, oops.Errorf("unsupported key type %s", jwk.Kty)
+}
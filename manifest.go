@@ -0,0 +1,168 @@
+package oteleport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// manifestObjectName is the name of the marker object of a non-empty partition.
+// markers are written at `manifest/<signal>/<partition>/partition.json` in the default partition style,
+// so that fetch lists markers once, instead of listing every partition in the time range.
+const manifestObjectName = "partition.json"
+
+// manifestPurgeMargin is the margin of partitions which may be purged, for clocks of instances skewed from each other.
+const manifestPurgeMargin = time.Hour
+
+type TimeRange struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// manifestKey returns the marker object key of the partition of t.
+func (r *ObjectSignalRepository) manifestKey(signal string, t time.Time) string {
+	partition := r.partitioner.truncate(t).Format(r.partitioner.layoutOf(PartitionStyleDefault))
+	return r.objectKeyPrefix(fmt.Sprintf("manifest/%s/%s/%s", signal, partition, manifestObjectName))
+}
+
+// markPartition writes the marker of the partition, once per process for partitions never purged.
+// partition is the partition path of written objects, in the configured style.
+func (r *ObjectSignalRepository) markPartition(ctx context.Context, signal string, partition string) error {
+	if !r.manifest {
+		return nil
+	}
+	t, ok := r.partitioner.parse(partition+"/", r.partitioner.style)
+	if !ok {
+		return oops.Errorf("failed to parse partition %q", partition)
+	}
+	return r.putManifest(ctx, signal, t)
+}
+
+func (r *ObjectSignalRepository) putManifest(ctx context.Context, signal string, t time.Time) error {
+	key := r.manifestKey(signal, t)
+	// markers of partitions which may be purged are always written, the marker may be purged by other instances,
+	// which never clear the cache of this instance.
+	cacheable := !r.mayBePurged(signal, t)
+	if _, ok := r.manifestMarked.Load(key); ok && cacheable {
+		return nil
+	}
+	body, err := json.Marshal(&TimeRange{
+		StartTime: t,
+		EndTime:   r.partitioner.next(t),
+	})
+	if err != nil {
+		return oops.Wrapf(err, "failed to marshal manifest")
+	}
+	if err := r.storage.PutObject(ctx, key, bytes.NewReader(body), &putObjectOptions{ContentType: "application/json"}); err != nil {
+		return oops.Wrapf(err, "failed to put manifest")
+	}
+	if cacheable {
+		r.manifestMarked.Store(key, struct{}{})
+	}
+	return nil
+}
+
+// mayBePurged reports whether the partition of t may be purged by the retention of the signal.
+func (r *ObjectSignalRepository) mayBePurged(signal string, t time.Time) bool {
+	var retention time.Duration
+	switch signal {
+	case "traces":
+		retention = r.retention.traces
+	case "metrics":
+		retention = r.retention.metrics
+	case "logs":
+		retention = r.retention.logs
+	}
+	if retention == 0 {
+		return false
+	}
+	return r.partitioner.next(t).Before(time.Now().Add(-retention + manifestPurgeMargin))
+}
+
+// loadManifest returns the marker object keys of non-empty partitions between startTime and endTime.
+func (r *ObjectSignalRepository) loadManifest(ctx context.Context, signal string, startTime time.Time, endTime time.Time) (map[string]bool, error) {
+	startAfter := strings.TrimSuffix(r.manifestKey(signal, startTime), "/"+manifestObjectName)
+	lastKey := r.manifestKey(signal, endTime)
+	partitions := make(map[string]bool)
+	_, err := r.storage.ListObjects(ctx, r.objectKeyPrefix(fmt.Sprintf("manifest/%s/", signal)), &startAfter, func(obj storageObject) (bool, error) {
+		if obj.Key > lastKey {
+			return false, nil
+		}
+		partitions[obj.Key] = true
+		return true, nil
+	})
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to list manifest")
+	}
+	slog.DebugContext(ctx, "load manifest", "signal", signal, "start_time", startTime, "end_time", endTime, "partitions", len(partitions))
+	return partitions, nil
+}
+
+// TimeRanges returns the time ranges of stored signals, continuous non-empty partitions are merged into a range.
+func (r *ObjectSignalRepository) TimeRanges(ctx context.Context, signal string) ([]*TimeRange, error) {
+	if !r.manifest {
+		return nil, oops.Errorf("manifest is not enabled")
+	}
+	prefix := r.objectKeyPrefix(fmt.Sprintf("manifest/%s/", signal))
+	ranges := make([]*TimeRange, 0)
+	_, err := r.storage.ListObjects(ctx, prefix, nil, func(obj storageObject) (bool, error) {
+		t, ok := r.partitioner.parse(strings.TrimPrefix(obj.Key, prefix), PartitionStyleDefault)
+		if !ok {
+			return true, nil
+		}
+		if len(ranges) > 0 && ranges[len(ranges)-1].EndTime.Equal(t) {
+			ranges[len(ranges)-1].EndTime = r.partitioner.next(t)
+			return true, nil
+		}
+		ranges = append(ranges, &TimeRange{
+			StartTime: t,
+			EndTime:   r.partitioner.next(t),
+		})
+		return true, nil
+	})
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to list manifest")
+	}
+	return ranges, nil
+}
+
+// RebuildManifest writes markers of all partitions with stored objects, like written before the manifest is enabled.
+// it returns the number of partitions found.
+func (r *ObjectSignalRepository) RebuildManifest(ctx context.Context, signal string) (int, error) {
+	if !r.manifest {
+		return 0, oops.Errorf("manifest is not enabled")
+	}
+	prefix := r.objectKeyPrefix(signal + "/")
+	found := make(map[string]bool)
+	_, err := r.storage.ListObjects(ctx, prefix, nil, func(obj storageObject) (bool, error) {
+		rel := strings.TrimPrefix(obj.Key, prefix)
+		style := PartitionStyleDefault
+		if strings.HasPrefix(rel, "year=") {
+			style = PartitionStyleHive
+		}
+		t, ok := r.partitioner.parse(rel, style)
+		if !ok {
+			slog.DebugContext(ctx, "skip object not in partition", "key", obj.Key)
+			return true, nil
+		}
+		key := r.manifestKey(signal, t)
+		if found[key] {
+			return true, nil
+		}
+		found[key] = true
+		if err := r.putManifest(ctx, signal, t); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return len(found), oops.Wrapf(err, "failed to rebuild manifest")
+	}
+	return len(found), nil
+}
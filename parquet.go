@@ -0,0 +1,476 @@
+package oteleport
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/mashiike/go-otlp-helper/otlp"
+	oteleportpb "github.com/mashiike/oteleport/proto"
+	"github.com/parquet-go/parquet-go"
+	"github.com/samber/oops"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// parquetMagic is the magic number at the head of parquet files.
+var parquetMagic = []byte("PAR1")
+
+// parquet rows of flattened signals.
+// scalar fields are stored as typed columns for columnar pruning,
+// and nested fields (attributes, events, links, data points, ...) are stored as OTLP JSON strings.
+// column names are the same as the flattened JSON lines.
+
+type parquetSpan struct {
+	ResourceAttributes             string `parquet:"resourceAttributes,optional"`
+	DroppedResourceAttributesCount int32  `parquet:"droppedResourceAttributesCount"`
+	ResourceSpanSchemaURL          string `parquet:"resourceSpanSchemaUrl,optional"`
+	ScopeName                      string `parquet:"scopeName,optional"`
+	ScopeVersion                   string `parquet:"scopeVersion,optional"`
+	ScopeAttributes                string `parquet:"scopeAttributes,optional"`
+	DroppedScopeAttributesCount    int32  `parquet:"droppedScopeAttributesCount"`
+	ScopeSpanSchemaURL             string `parquet:"scopeSpanSchemaUrl,optional"`
+	TraceID                        string `parquet:"traceId"`
+	SpanID                         string `parquet:"spanId"`
+	TraceState                     string `parquet:"traceState,optional"`
+	ParentSpanID                   string `parquet:"parentSpanId,optional"`
+	Name                           string `parquet:"name"`
+	Kind                           int32  `parquet:"kind"`
+	StartTimeUnixNano              int64  `parquet:"startTimeUnixNano"`
+	EndTimeUnixNano                int64  `parquet:"endTimeUnixNano"`
+	Attributes                     string `parquet:"attributes,optional"`
+	DroppedAttributesCount         int32  `parquet:"droppedAttributesCount"`
+	Events                         string `parquet:"events,optional"`
+	DroppedEventsCount             int32  `parquet:"droppedEventsCount"`
+	Links                          string `parquet:"links,optional"`
+	DroppedLinksCount              int32  `parquet:"droppedLinksCount"`
+	Status                         string `parquet:"status,optional"`
+	Flags                          int32  `parquet:"flags"`
+}
+
+type parquetDataPoint struct {
+	ResourceAttributes             string `parquet:"resourceAttributes,optional"`
+	DroppedResourceAttributesCount int32  `parquet:"droppedResourceAttributesCount"`
+	ResourceMetricSchemaURL        string `parquet:"resourceMetricSchemaUrl,optional"`
+	ScopeName                      string `parquet:"scopeName,optional"`
+	ScopeVersion                   string `parquet:"scopeVersion,optional"`
+	ScopeAttributes                string `parquet:"scopeAttributes,optional"`
+	DroppedScopeAttributesCount    int32  `parquet:"droppedScopeAttributesCount"`
+	ScopeMetricSchemaURL           string `parquet:"scopeMetricSchemaUrl,optional"`
+	Name                           string `parquet:"name"`
+	Description                    string `parquet:"description,optional"`
+	Unit                           string `parquet:"unit,optional"`
+	Gauge                          string `parquet:"gauge,optional"`
+	Sum                            string `parquet:"sum,optional"`
+	Histogram                      string `parquet:"histogram,optional"`
+	ExponentialHistogram           string `parquet:"exponentialHistogram,optional"`
+	Summary                        string `parquet:"summary,optional"`
+	Metadata                       string `parquet:"metadata,optional"`
+	StartTimeUnixNano              int64  `parquet:"startTimeUnixNano"`
+	TimeUnixNano                   int64  `parquet:"timeUnixNano"`
+}
+
+type parquetLogRecord struct {
+	ResourceAttributes             string `parquet:"resourceAttributes,optional"`
+	DroppedResourceAttributesCount int32  `parquet:"droppedResourceAttributesCount"`
+	ResourceLogSchemaURL           string `parquet:"resourceLogSchemaUrl,optional"`
+	ScopeName                      string `parquet:"scopeName,optional"`
+	ScopeVersion                   string `parquet:"scopeVersion,optional"`
+	ScopeAttributes                string `parquet:"scopeAttributes,optional"`
+	DroppedScopeAttributesCount    int32  `parquet:"droppedScopeAttributesCount"`
+	ScopeLogSchemaURL              string `parquet:"scopeLogSchemaUrl,optional"`
+	TimeUnixNano                   int64  `parquet:"timeUnixNano"`
+	SeverityNumber                 int32  `parquet:"severityNumber"`
+	SeverityText                   string `parquet:"severityText,optional"`
+	Body                           string `parquet:"body,optional"`
+	Attributes                     string `parquet:"attributes,optional"`
+	DroppedAttributesCount         int32  `parquet:"droppedAttributesCount"`
+	Flags                          int32  `parquet:"flags"`
+	TraceID                        string `parquet:"traceId,optional"`
+	SpanID                         string `parquet:"spanId,optional"`
+	ObservedTimeUnixNano           int64  `parquet:"observedTimeUnixNano"`
+}
+
+func writeParquet[T any](rows []T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows, parquet.Compression(&parquet.Snappy)); err != nil {
+		return nil, oops.Wrapf(err, "failed to write parquet")
+	}
+	return buf.Bytes(), nil
+}
+
+func readParquet[T any](body []byte) ([]T, error) {
+	rows, err := parquet.Read[T](bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to read parquet")
+	}
+	return rows, nil
+}
+
+func encodeParquetSpans(spans []*oteleportpb.FlattenSpan) ([]byte, error) {
+	rows := make([]parquetSpan, 0, len(spans))
+	for _, s := range spans {
+		row := parquetSpan{
+			DroppedResourceAttributesCount: int32(s.GetDroppedResourceAttributesCount()),
+			ResourceSpanSchemaURL:          s.GetResourceSpanSchemaUrl(),
+			ScopeName:                      s.GetScopeName(),
+			ScopeVersion:                   s.GetScopeVersion(),
+			DroppedScopeAttributesCount:    int32(s.GetDroppedScopeAttributesCount()),
+			ScopeSpanSchemaURL:             s.GetScopeSpanSchemaUrl(),
+			TraceID:                        hex.EncodeToString(s.GetTraceId()),
+			SpanID:                         hex.EncodeToString(s.GetSpanId()),
+			TraceState:                     s.GetTraceState(),
+			ParentSpanID:                   hex.EncodeToString(s.GetParentSpanId()),
+			Name:                           s.GetName(),
+			Kind:                           int32(s.GetKind()),
+			StartTimeUnixNano:              int64(s.GetStartTimeUnixNano()),
+			EndTimeUnixNano:                int64(s.GetEndTimeUnixNano()),
+			DroppedAttributesCount:         int32(s.GetDroppedAttributesCount()),
+			DroppedEventsCount:             int32(s.GetDroppedEventsCount()),
+			DroppedLinksCount:              int32(s.GetDroppedLinksCount()),
+			Flags:                          int32(s.GetFlags()),
+		}
+		var err error
+		if row.ResourceAttributes, err = marshalJSONList(s.GetResourceAttributes()); err != nil {
+			return nil, err
+		}
+		if row.ScopeAttributes, err = marshalJSONList(s.GetScopeAttributes()); err != nil {
+			return nil, err
+		}
+		if row.Attributes, err = marshalJSONList(s.GetAttributes()); err != nil {
+			return nil, err
+		}
+		if row.Events, err = marshalJSONList(s.GetEvents()); err != nil {
+			return nil, err
+		}
+		if row.Links, err = marshalJSONList(s.GetLinks()); err != nil {
+			return nil, err
+		}
+		if row.Status, err = marshalJSONMessage(s.GetStatus()); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return writeParquet(rows)
+}
+
+func decodeParquetSpans(body []byte) ([]*oteleportpb.FlattenSpan, error) {
+	rows, err := readParquet[parquetSpan](body)
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]*oteleportpb.FlattenSpan, 0, len(rows))
+	for _, row := range rows {
+		s := &oteleportpb.FlattenSpan{
+			DroppedResourceAttributesCount: uint32(row.DroppedResourceAttributesCount),
+			ResourceSpanSchemaUrl:          row.ResourceSpanSchemaURL,
+			ScopeName:                      row.ScopeName,
+			ScopeVersion:                   row.ScopeVersion,
+			DroppedScopeAttributesCount:    uint32(row.DroppedScopeAttributesCount),
+			ScopeSpanSchemaUrl:             row.ScopeSpanSchemaURL,
+			TraceState:                     row.TraceState,
+			Name:                           row.Name,
+			Kind:                           tracepb.Span_SpanKind(row.Kind),
+			StartTimeUnixNano:              uint64(row.StartTimeUnixNano),
+			EndTimeUnixNano:                uint64(row.EndTimeUnixNano),
+			DroppedAttributesCount:         uint32(row.DroppedAttributesCount),
+			DroppedEventsCount:             uint32(row.DroppedEventsCount),
+			DroppedLinksCount:              uint32(row.DroppedLinksCount),
+			Flags:                          uint32(row.Flags),
+		}
+		if s.TraceId, err = decodeHexID(row.TraceID); err != nil {
+			return nil, err
+		}
+		if s.SpanId, err = decodeHexID(row.SpanID); err != nil {
+			return nil, err
+		}
+		if s.ParentSpanId, err = decodeHexID(row.ParentSpanID); err != nil {
+			return nil, err
+		}
+		if s.ResourceAttributes, err = unmarshalJSONList(row.ResourceAttributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if s.ScopeAttributes, err = unmarshalJSONList(row.ScopeAttributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if s.Attributes, err = unmarshalJSONList(row.Attributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if s.Events, err = unmarshalJSONList(row.Events, func() *tracepb.Span_Event { return &tracepb.Span_Event{} }); err != nil {
+			return nil, err
+		}
+		if s.Links, err = unmarshalJSONList(row.Links, func() *tracepb.Span_Link { return &tracepb.Span_Link{} }); err != nil {
+			return nil, err
+		}
+		if s.Status, err = unmarshalJSONMessage(row.Status, &tracepb.Status{}); err != nil {
+			return nil, err
+		}
+		spans = append(spans, s)
+	}
+	return spans, nil
+}
+
+func encodeParquetDataPoints(dataPoints []*oteleportpb.FlattenDataPoint) ([]byte, error) {
+	rows := make([]parquetDataPoint, 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		row := parquetDataPoint{
+			DroppedResourceAttributesCount: int32(dp.GetDroppedResourceAttributesCount()),
+			ResourceMetricSchemaURL:        dp.GetResourceMetricSchemaUrl(),
+			ScopeName:                      dp.GetScopeName(),
+			ScopeVersion:                   dp.GetScopeVersion(),
+			DroppedScopeAttributesCount:    int32(dp.GetDroppedScopeAttributesCount()),
+			ScopeMetricSchemaURL:           dp.GetScopeMetricSchemaUrl(),
+			Name:                           dp.GetName(),
+			Description:                    dp.GetDescription(),
+			Unit:                           dp.GetUnit(),
+			StartTimeUnixNano:              int64(dp.GetStartTimeUnixNano()),
+			TimeUnixNano:                   int64(dp.GetTimeUnixNano()),
+		}
+		var err error
+		if row.ResourceAttributes, err = marshalJSONList(dp.GetResourceAttributes()); err != nil {
+			return nil, err
+		}
+		if row.ScopeAttributes, err = marshalJSONList(dp.GetScopeAttributes()); err != nil {
+			return nil, err
+		}
+		if row.Metadata, err = marshalJSONList(dp.GetMetadata()); err != nil {
+			return nil, err
+		}
+		switch data := dp.GetData().(type) {
+		case *oteleportpb.FlattenDataPoint_Gauge:
+			row.Gauge, err = marshalJSONMessage(data.Gauge)
+		case *oteleportpb.FlattenDataPoint_Sum:
+			row.Sum, err = marshalJSONMessage(data.Sum)
+		case *oteleportpb.FlattenDataPoint_Histogram:
+			row.Histogram, err = marshalJSONMessage(data.Histogram)
+		case *oteleportpb.FlattenDataPoint_ExponentialHistogram:
+			row.ExponentialHistogram, err = marshalJSONMessage(data.ExponentialHistogram)
+		case *oteleportpb.FlattenDataPoint_Summary:
+			row.Summary, err = marshalJSONMessage(data.Summary)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return writeParquet(rows)
+}
+
+func decodeParquetDataPoints(body []byte) ([]*oteleportpb.FlattenDataPoint, error) {
+	rows, err := readParquet[parquetDataPoint](body)
+	if err != nil {
+		return nil, err
+	}
+	dataPoints := make([]*oteleportpb.FlattenDataPoint, 0, len(rows))
+	for _, row := range rows {
+		dp := &oteleportpb.FlattenDataPoint{
+			DroppedResourceAttributesCount: uint32(row.DroppedResourceAttributesCount),
+			ResourceMetricSchemaUrl:        row.ResourceMetricSchemaURL,
+			ScopeName:                      row.ScopeName,
+			ScopeVersion:                   row.ScopeVersion,
+			DroppedScopeAttributesCount:    uint32(row.DroppedScopeAttributesCount),
+			ScopeMetricSchemaUrl:           row.ScopeMetricSchemaURL,
+			Name:                           row.Name,
+			Description:                    row.Description,
+			Unit:                           row.Unit,
+			StartTimeUnixNano:              uint64(row.StartTimeUnixNano),
+			TimeUnixNano:                   uint64(row.TimeUnixNano),
+		}
+		if dp.ResourceAttributes, err = unmarshalJSONList(row.ResourceAttributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if dp.ScopeAttributes, err = unmarshalJSONList(row.ScopeAttributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if dp.Metadata, err = unmarshalJSONList(row.Metadata, newKeyValue); err != nil {
+			return nil, err
+		}
+		switch {
+		case row.Gauge != "":
+			data := &oteleportpb.FlattenDataPoint_Gauge{}
+			data.Gauge, err = unmarshalJSONMessage(row.Gauge, &oteleportpb.FlattenGuage{})
+			dp.Data = data
+		case row.Sum != "":
+			data := &oteleportpb.FlattenDataPoint_Sum{}
+			data.Sum, err = unmarshalJSONMessage(row.Sum, &oteleportpb.FlattenSum{})
+			dp.Data = data
+		case row.Histogram != "":
+			data := &oteleportpb.FlattenDataPoint_Histogram{}
+			data.Histogram, err = unmarshalJSONMessage(row.Histogram, &oteleportpb.FlattenHistogram{})
+			dp.Data = data
+		case row.ExponentialHistogram != "":
+			data := &oteleportpb.FlattenDataPoint_ExponentialHistogram{}
+			data.ExponentialHistogram, err = unmarshalJSONMessage(row.ExponentialHistogram, &oteleportpb.FlattenExponentialHistogram{})
+			dp.Data = data
+		case row.Summary != "":
+			data := &oteleportpb.FlattenDataPoint_Summary{}
+			data.Summary, err = unmarshalJSONMessage(row.Summary, &oteleportpb.FlattenSummary{})
+			dp.Data = data
+		}
+		if err != nil {
+			return nil, err
+		}
+		dataPoints = append(dataPoints, dp)
+	}
+	return dataPoints, nil
+}
+
+func encodeParquetLogRecords(logRecords []*oteleportpb.FlattenLogRecord) ([]byte, error) {
+	rows := make([]parquetLogRecord, 0, len(logRecords))
+	for _, lr := range logRecords {
+		row := parquetLogRecord{
+			DroppedResourceAttributesCount: int32(lr.GetDroppedResourceAttributesCount()),
+			ResourceLogSchemaURL:           lr.GetResourceLogSchemaUrl(),
+			ScopeName:                      lr.GetScopeName(),
+			ScopeVersion:                   lr.GetScopeVersion(),
+			DroppedScopeAttributesCount:    int32(lr.GetDroppedScopeAttributesCount()),
+			ScopeLogSchemaURL:              lr.GetScopeLogSchemaUrl(),
+			TimeUnixNano:                   int64(lr.GetTimeUnixNano()),
+			SeverityNumber:                 int32(lr.GetSeverityNumber()),
+			SeverityText:                   lr.GetSeverityText(),
+			DroppedAttributesCount:         int32(lr.GetDroppedAttributesCount()),
+			Flags:                          int32(lr.GetFlags()),
+			TraceID:                        hex.EncodeToString(lr.GetTraceId()),
+			SpanID:                         hex.EncodeToString(lr.GetSpanId()),
+			ObservedTimeUnixNano:           int64(lr.GetObservedTimeUnixNano()),
+		}
+		var err error
+		if row.ResourceAttributes, err = marshalJSONList(lr.GetResourceAttributes()); err != nil {
+			return nil, err
+		}
+		if row.ScopeAttributes, err = marshalJSONList(lr.GetScopeAttributes()); err != nil {
+			return nil, err
+		}
+		if row.Attributes, err = marshalJSONList(lr.GetAttributes()); err != nil {
+			return nil, err
+		}
+		if row.Body, err = marshalJSONMessage(lr.GetBody()); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return writeParquet(rows)
+}
+
+func decodeParquetLogRecords(body []byte) ([]*oteleportpb.FlattenLogRecord, error) {
+	rows, err := readParquet[parquetLogRecord](body)
+	if err != nil {
+		return nil, err
+	}
+	logRecords := make([]*oteleportpb.FlattenLogRecord, 0, len(rows))
+	for _, row := range rows {
+		lr := &oteleportpb.FlattenLogRecord{
+			DroppedResourceAttributesCount: uint32(row.DroppedResourceAttributesCount),
+			ResourceLogSchemaUrl:           row.ResourceLogSchemaURL,
+			ScopeName:                      row.ScopeName,
+			ScopeVersion:                   row.ScopeVersion,
+			DroppedScopeAttributesCount:    uint32(row.DroppedScopeAttributesCount),
+			ScopeLogSchemaUrl:              row.ScopeLogSchemaURL,
+			TimeUnixNano:                   uint64(row.TimeUnixNano),
+			SeverityNumber:                 logspb.SeverityNumber(row.SeverityNumber),
+			SeverityText:                   row.SeverityText,
+			DroppedAttributesCount:         uint32(row.DroppedAttributesCount),
+			Flags:                          uint32(row.Flags),
+			ObservedTimeUnixNano:           uint64(row.ObservedTimeUnixNano),
+		}
+		if lr.TraceId, err = decodeHexID(row.TraceID); err != nil {
+			return nil, err
+		}
+		if lr.SpanId, err = decodeHexID(row.SpanID); err != nil {
+			return nil, err
+		}
+		if lr.ResourceAttributes, err = unmarshalJSONList(row.ResourceAttributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if lr.ScopeAttributes, err = unmarshalJSONList(row.ScopeAttributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if lr.Attributes, err = unmarshalJSONList(row.Attributes, newKeyValue); err != nil {
+			return nil, err
+		}
+		if lr.Body, err = unmarshalJSONMessage(row.Body, &commonpb.AnyValue{}); err != nil {
+			return nil, err
+		}
+		logRecords = append(logRecords, lr)
+	}
+	return logRecords, nil
+}
+
+func newKeyValue() *commonpb.KeyValue {
+	return &commonpb.KeyValue{}
+}
+
+func decodeHexID(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to decode id %q", s)
+	}
+	return bs, nil
+}
+
+// marshalJSONList encodes messages as a JSON array of OTLP JSON, empty list is encoded as empty string.
+func marshalJSONList[T proto.Message](list []T) (string, error) {
+	if len(list) == 0 {
+		return "", nil
+	}
+	values := make([]json.RawMessage, 0, len(list))
+	for _, m := range list {
+		bs, err := otlp.MarshalJSON(m)
+		if err != nil {
+			return "", oops.Wrapf(err, "failed to marshal json")
+		}
+		values = append(values, bs)
+	}
+	bs, err := json.Marshal(values)
+	if err != nil {
+		return "", oops.Wrapf(err, "failed to marshal json")
+	}
+	return string(bs), nil
+}
+
+func unmarshalJSONList[T proto.Message](s string, newFunc func() T) ([]T, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		return nil, oops.Wrapf(err, "failed to unmarshal json")
+	}
+	list := make([]T, 0, len(values))
+	for _, v := range values {
+		m := newFunc()
+		if err := otlp.UnmarshalJSON(v, m); err != nil {
+			return nil, oops.Wrapf(err, "failed to unmarshal json")
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// marshalJSONMessage encodes the message as OTLP JSON, nil message is encoded as empty string.
+func marshalJSONMessage(m proto.Message) (string, error) {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return "", nil
+	}
+	bs, err := otlp.MarshalJSON(m)
+	if err != nil {
+		return "", oops.Wrapf(err, "failed to marshal json")
+	}
+	return string(bs), nil
+}
+
+func unmarshalJSONMessage[T proto.Message](s string, m T) (T, error) {
+	var zero T
+	if s == "" {
+		return zero, nil
+	}
+	if err := otlp.UnmarshalJSON([]byte(s), m); err != nil {
+		return zero, oops.Wrapf(err, "failed to unmarshal json")
+	}
+	return m, nil
+}
@@ -0,0 +1,193 @@
+package oteleport
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	oteleportpb "github.com/mashiike/oteleport/proto"
+	"github.com/samber/lo"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// hiveDefaultPartition is the partition value for missing values, same as Hive.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// partitioner decides the partition of object keys.
+// partitions are aligned to the granularity in the configured timezone,
+// and optionally followed by the resource attribute partition, like `service=api`.
+type partitioner struct {
+	granularity       string
+	location          *time.Location
+	style             string
+	resourceAttribute string
+}
+
+func newPartitioner(cfg *StorageConfig) *partitioner {
+	p := &partitioner{
+		granularity:       cfg.Partition.Granularity,
+		location:          cfg.Partition.location,
+		style:             cfg.PartitionStyle,
+		resourceAttribute: cfg.Partition.ResourceAttribute,
+	}
+	if p.granularity == "" {
+		p.granularity = PartitionGranularityHour
+	}
+	if p.location == nil {
+		p.location = time.UTC
+	}
+	if p.style == "" {
+		p.style = PartitionStyleDefault
+	}
+	return p
+}
+
+// layout returns the time layout of the partition path in the configured style.
+func (p *partitioner) layout() string {
+	return p.layoutOf(p.style)
+}
+
+func (p *partitioner) layoutOf(style string) string {
+	if style == PartitionStyleHive {
+		switch p.granularity {
+		case PartitionGranularityMinute:
+			return "year=2006/month=01/day=02/hour=15/minute=04"
+		case PartitionGranularityDay:
+			return "year=2006/month=01/day=02"
+		default:
+			return "year=2006/month=01/day=02/hour=15"
+		}
+	}
+	switch p.granularity {
+	case PartitionGranularityMinute:
+		return "2006/01/02/15/04"
+	case PartitionGranularityDay:
+		return "2006/01/02"
+	default:
+		return "2006/01/02/15"
+	}
+}
+
+// format returns the partition path of t in the configured style.
+func (p *partitioner) format(t time.Time) string {
+	return t.In(p.location).Format(p.layout())
+}
+
+// formats returns the partition paths of t in all styles, for reading objects written in any style.
+// the order is the same as the lexicographic order of the paths.
+func (p *partitioner) formats(t time.Time) []string {
+	t = t.In(p.location)
+	return []string{
+		t.Format(p.layoutOf(PartitionStyleDefault)),
+		t.Format(p.layoutOf(PartitionStyleHive)),
+	}
+}
+
+// parse returns the start time of the partition in the style, from the object key relative to the signal prefix.
+func (p *partitioner) parse(key string, style string) (time.Time, bool) {
+	layout := p.layoutOf(style)
+	segments := strings.SplitN(key, "/", strings.Count(layout, "/")+2)
+	if len(segments) <= strings.Count(layout, "/")+1 {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(layout, strings.Join(segments[:len(segments)-1], "/"), p.location)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// parseEnd returns the end time of the partition of the object key in the style, in any granularity,
+// so that objects written before the granularity is changed are also parsed. finer granularities are tried first.
+func (p *partitioner) parseEnd(key string, style string) (time.Time, bool) {
+	for _, granularity := range []string{PartitionGranularityMinute, PartitionGranularityHour, PartitionGranularityDay} {
+		q := *p
+		q.granularity = granularity
+		if t, ok := q.parse(key, style); ok {
+			return q.next(t), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// withResource appends the resource attribute partition to the time partition path.
+func (p *partitioner) withResource(partition string, resource *resourcepb.Resource) string {
+	if p.resourceAttribute == "" {
+		return partition
+	}
+	var value string
+	for _, kv := range resource.GetAttributes() {
+		if kv.GetKey() == p.resourceAttribute {
+			value = anyValueString(kv.GetValue())
+			break
+		}
+	}
+	return path.Join(partition, p.resourcePartition(value))
+}
+
+func (p *partitioner) resourcePartition(value string) string {
+	if value == "" {
+		value = hiveDefaultPartition
+	}
+	return fmt.Sprintf("%s=%s", resourcePartitionName(p.resourceAttribute), url.PathEscape(value))
+}
+
+// filterResourcePartition returns the resource partition of the value of the resource attribute matchers, like `service=api`.
+// it returns an empty string when the matchers have no value for the partition attribute, then all resource partitions are read.
+func (p *partitioner) filterResourcePartition(matchers []*oteleportpb.AttributeMatcher) string {
+	if p.resourceAttribute == "" {
+		return ""
+	}
+	m, ok := lo.Find(matchers, func(m *oteleportpb.AttributeMatcher) bool {
+		return m.GetKey() == p.resourceAttribute
+	})
+	if !ok {
+		return ""
+	}
+	return p.resourcePartition(m.GetValue())
+}
+
+// resourcePartitionName returns the partition column name of the resource attribute.
+// service.name is the most common dimension, and is named `service`.
+func resourcePartitionName(attributeKey string) string {
+	if attributeKey == "service.name" {
+		return "service"
+	}
+	return strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '_' {
+			return r
+		}
+		if 'A' <= r && r <= 'Z' {
+			return r - 'A' + 'a'
+		}
+		return '_'
+	}, attributeKey)
+}
+
+// truncate returns the start time of the partition that contains t.
+func (p *partitioner) truncate(t time.Time) time.Time {
+	t = t.In(p.location)
+	switch p.granularity {
+	case PartitionGranularityMinute:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, p.location)
+	case PartitionGranularityDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.location)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, p.location)
+	}
+}
+
+// next returns the start time of the partition after the partition started at t.
+func (p *partitioner) next(t time.Time) time.Time {
+	switch p.granularity {
+	case PartitionGranularityMinute:
+		return t.Add(time.Minute)
+	case PartitionGranularityDay:
+		// AddDate keeps days aligned over DST transitions.
+		return t.AddDate(0, 0, 1)
+	default:
+		return p.truncate(t.Add(time.Hour))
+	}
+}
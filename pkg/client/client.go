@@ -9,6 +9,7 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/mashiike/go-otlp-helper/otlp"
 	oteleportpb "github.com/mashiike/oteleport/proto"
@@ -89,6 +90,27 @@ func (c *Client) FetchLogsData(ctx context.Context, req *oteleportpb.FetchLogsDa
 	return &resp, nil
 }
 
+func (c *Client) GetTrace(ctx context.Context, req *oteleportpb.GetTraceRequest) (*oteleportpb.GetTraceResponse, error) {
+	query := url.Values{}
+	if req.GetStartTimeUnixNano() != 0 {
+		query.Set("start_time_unix_nano", strconv.FormatUint(req.GetStartTimeUnixNano(), 10))
+	}
+	if req.GetEndTimeUnixNano() != 0 {
+		query.Set("end_time_unix_nano", strconv.FormatUint(req.GetEndTimeUnixNano(), 10))
+	}
+	u := c.endpointURL.JoinPath("/api/traces", url.PathEscape(req.GetTraceId()))
+	u.RawQuery = query.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to create request")
+	}
+	var resp oteleportpb.GetTraceResponse
+	if err := c.send(ctx, httpReq, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 type FetchTracesDataPagenator struct {
 	c           *Client
 	req         *oteleportpb.FetchTracesDataRequest
@@ -234,6 +256,11 @@ func (c *Client) do(ctx context.Context, path string, protoBody proto.Message, r
 		return oops.Wrapf(err, "failed to create request")
 	}
 	req.Header.Set("Content-Type", "application/x-protobuf")
+	return c.send(ctx, req, respBody)
+}
+
+func (c *Client) send(ctx context.Context, req *http.Request, respBody proto.Message) error {
+	req.Header.Set("Accept", "application/x-protobuf")
 	if c.p.AccessKey != "" {
 		req.Header.Set(c.p.AccessKeyHeader, c.p.AccessKey)
 	}
@@ -250,10 +277,10 @@ func (c *Client) do(ctx context.Context, path string, protoBody proto.Message, r
 		var protoStatus spb.Status
 		if unmarshalErr := unmarshalBody(resp.Header.Get("Content-Type"), respBodyBytes, &protoStatus); unmarshalErr != nil {
 			slog.WarnContext(ctx, "failed to unmarshal response body", "message", unmarshalErr.Error())
-			return oops.Errorf("failed fetch traces data: status code %d", resp.StatusCode)
+			return oops.Errorf("failed request %s: status code %d", req.URL.Path, resp.StatusCode)
 		}
 		st := status.FromProto(&protoStatus)
-		return oops.Errorf("faild fetch traces data: code=%s, message=%s", st.Code(), st.Message())
+		return oops.Errorf("failed request %s: code=%s, message=%s", req.URL.Path, st.Code(), st.Message())
 	}
 	if err := unmarshalBody(resp.Header.Get("Content-Type"), respBodyBytes, respBody); err != nil {
 		return oops.Wrapf(err, "failed to unmarshal response body")
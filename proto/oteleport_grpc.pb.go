@@ -22,6 +22,7 @@ const (
 	OterlportService_FetchTracesData_FullMethodName  = "/oteleport.proto.v1.OterlportService/FetchTracesData"
 	OterlportService_FetchMetricsData_FullMethodName = "/oteleport.proto.v1.OterlportService/FetchMetricsData"
 	OterlportService_FetchLogsData_FullMethodName    = "/oteleport.proto.v1.OterlportService/FetchLogsData"
+	OterlportService_GetTrace_FullMethodName         = "/oteleport.proto.v1.OterlportService/GetTrace"
 )
 
 // OterlportServiceClient is the client API for OterlportService service.
@@ -31,6 +32,7 @@ type OterlportServiceClient interface {
 	FetchTracesData(ctx context.Context, in *FetchTracesDataRequest, opts ...grpc.CallOption) (*FetchTracesDataResponse, error)
 	FetchMetricsData(ctx context.Context, in *FetchMetricsDataRequest, opts ...grpc.CallOption) (*FetchMetricsDataResponse, error)
 	FetchLogsData(ctx context.Context, in *FetchLogsDataRequest, opts ...grpc.CallOption) (*FetchLogsDataResponse, error)
+	GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (*GetTraceResponse, error)
 }
 
 type oterlportServiceClient struct {
@@ -71,6 +73,16 @@ func (c *oterlportServiceClient) FetchLogsData(ctx context.Context, in *FetchLog
 	return out, nil
 }
 
+func (c *oterlportServiceClient) GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (*GetTraceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTraceResponse)
+	err := c.cc.Invoke(ctx, OterlportService_GetTrace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OterlportServiceServer is the server API for OterlportService service.
 // All implementations must embed UnimplementedOterlportServiceServer
 // for forward compatibility.
@@ -78,6 +90,7 @@ type OterlportServiceServer interface {
 	FetchTracesData(context.Context, *FetchTracesDataRequest) (*FetchTracesDataResponse, error)
 	FetchMetricsData(context.Context, *FetchMetricsDataRequest) (*FetchMetricsDataResponse, error)
 	FetchLogsData(context.Context, *FetchLogsDataRequest) (*FetchLogsDataResponse, error)
+	GetTrace(context.Context, *GetTraceRequest) (*GetTraceResponse, error)
 	mustEmbedUnimplementedOterlportServiceServer()
 }
 
@@ -97,6 +110,9 @@ func (UnimplementedOterlportServiceServer) FetchMetricsData(context.Context, *Fe
 func (UnimplementedOterlportServiceServer) FetchLogsData(context.Context, *FetchLogsDataRequest) (*FetchLogsDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchLogsData not implemented")
 }
+func (UnimplementedOterlportServiceServer) GetTrace(context.Context, *GetTraceRequest) (*GetTraceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrace not implemented")
+}
 func (UnimplementedOterlportServiceServer) mustEmbedUnimplementedOterlportServiceServer() {}
 func (UnimplementedOterlportServiceServer) testEmbeddedByValue()                          {}
 
@@ -172,6 +188,24 @@ func _OterlportService_FetchLogsData_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OterlportService_GetTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OterlportServiceServer).GetTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OterlportService_GetTrace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OterlportServiceServer).GetTrace(ctx, req.(*GetTraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OterlportService_ServiceDesc is the grpc.ServiceDesc for OterlportService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +225,10 @@ var OterlportService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchLogsData",
 			Handler:    _OterlportService_FetchLogsData_Handler,
 		},
+		{
+			MethodName: "GetTrace",
+			Handler:    _OterlportService_GetTrace_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/oteleport.proto",
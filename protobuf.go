@@ -0,0 +1,91 @@
+package oteleport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/mashiike/go-otlp-helper/otlp"
+	"github.com/samber/oops"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// protobufObjectExt is the extension of objects of length-delimited OTLP data messages, like TracesData.
+	protobufObjectExt = "binpb"
+	// flattenProtobufObjectExt is the extension of objects of length-delimited flattened messages, like FlattenSpan.
+	flattenProtobufObjectExt = "flat.binpb"
+	// flattenJSONObjectExt is the extension of objects of JSON lines of flattened messages.
+	// flattened objects written before it have the `.json` extension.
+	flattenJSONObjectExt = "flat.json"
+)
+
+// encodeDelimited encodes messages as a stream of varint length-delimited messages.
+func encodeDelimited[T proto.Message](msgs []T) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		if _, err := protodelim.MarshalTo(&buf, msg); err != nil {
+			return nil, oops.Wrapf(err, "failed to marshal protobuf")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeDelimited decodes a stream of varint length-delimited messages.
+func decodeDelimited[T proto.Message](body []byte, newFunc func() T) ([]T, error) {
+	r := bufio.NewReader(bytes.NewReader(body))
+	msgs := make([]T, 0)
+	for {
+		msg := newFunc()
+		if err := (protodelim.UnmarshalOptions{MaxSize: -1}).UnmarshalFrom(r, msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return msgs, nil
+			}
+			return nil, oops.Wrapf(err, "failed to unmarshal protobuf")
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+// storageObjectFormat returns the format of the object by the key suffix.
+// parquet objects are also detected by the magic number, and objects of unknown suffix are json.
+func storageObjectFormat(obj storageObject, body []byte) string {
+	key := obj.Key
+	if codec := detectCompressionCodec(key, nil); codec != nil {
+		key = strings.TrimSuffix(key, codec.suffix)
+	}
+	switch {
+	case strings.HasSuffix(key, ".parquet"), bytes.HasPrefix(body, parquetMagic):
+		return StorageFormatParquet
+	case strings.HasSuffix(key, "."+protobufObjectExt):
+		return StorageFormatProtobuf
+	default:
+		return StorageFormatJSON
+	}
+}
+
+// isFlattenObject reports whether the protobuf or JSON object has flattened messages.
+func isFlattenObject(obj storageObject) bool {
+	key := obj.Key
+	if codec := detectCompressionCodec(key, nil); codec != nil {
+		key = strings.TrimSuffix(key, codec.suffix)
+	}
+	return strings.HasSuffix(key, "."+flattenProtobufObjectExt) || strings.HasSuffix(key, "."+flattenJSONObjectExt)
+}
+
+// decodeJSONLines decodes a stream of JSON messages.
+func decodeJSONLines[T proto.Message](body []byte, newFunc func() T) ([]T, error) {
+	dec := otlp.NewJSONDecoder(bytes.NewReader(body))
+	msgs := make([]T, 0)
+	for dec.More() {
+		msg := newFunc()
+		if err := dec.Decode(msg); err != nil {
+			return nil, oops.Wrapf(err, "failed to unmarshal json")
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
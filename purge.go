@@ -0,0 +1,145 @@
+package oteleport
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+type PurgeOptions struct {
+	// DryRun only counts the objects to delete.
+	DryRun bool
+	// Now is the base time of the retention, default is the current time.
+	Now time.Time
+}
+
+type PurgeResult struct {
+	// Tenant is empty for signals not of tenants.
+	Tenant  string
+	Signal  string
+	Cutoff  time.Time
+	Objects int
+	Bytes   int64
+}
+
+// Purge deletes partitions older than the retention of each signal.
+// a partition is deleted when the whole time range of the partition is before the cutoff.
+// the repository of the top level also purges signals of tenants in the storage.
+func (r *ObjectSignalRepository) Purge(ctx context.Context, opts *PurgeOptions) ([]*PurgeResult, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if err := r.discoverTenants(ctx); err != nil {
+		return nil, err
+	}
+	var results []*PurgeResult
+	for _, repo := range r.allRepositories() {
+		tenantResults, err := repo.purge(ctx, now, opts.DryRun)
+		results = append(results, tenantResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func (r *ObjectSignalRepository) purge(ctx context.Context, now time.Time, dryRun bool) ([]*PurgeResult, error) {
+	targets := []struct {
+		signal      string
+		retention   time.Duration
+		keyPrefixes []string
+	}{
+		{signal: "traces", retention: r.retention.traces, keyPrefixes: []string{"traces", "traces-index", "manifest/traces"}},
+		{signal: "metrics", retention: r.retention.metrics, keyPrefixes: []string{"metrics", "manifest/metrics"}},
+		{signal: "logs", retention: r.retention.logs, keyPrefixes: []string{"logs", "manifest/logs"}},
+	}
+	results := make([]*PurgeResult, 0, len(targets))
+	for _, target := range targets {
+		if target.retention == 0 {
+			continue
+		}
+		result := &PurgeResult{
+			Tenant: r.tenant,
+			Signal: target.signal,
+			Cutoff: now.Add(-target.retention),
+		}
+		for _, keyPrefix := range target.keyPrefixes {
+			if err := r.purgeObjects(ctx, keyPrefix, result, dryRun); err != nil {
+				return results, oops.Wrapf(err, "failed to purge %s", keyPrefix)
+			}
+		}
+		slog.InfoContext(ctx, "purge signals", "tenant", result.Tenant, "signal", result.Signal, "cutoff", result.Cutoff, "objects", result.Objects, "bytes", result.Bytes, "dry_run", dryRun)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// purgeObjects deletes objects under the key prefix in partitions before the cutoff.
+// the lexicographic order of keys is not the time order of partitions, like after the granularity or the timezone is changed,
+// so all objects of each style are listed, and objects of partitions not expired are skipped.
+func (r *ObjectSignalRepository) purgeObjects(ctx context.Context, keyPrefix string, result *PurgeResult, dryRun bool) error {
+	signalPrefix := r.objectKeyPrefix(keyPrefix + "/")
+	for _, style := range []string{PartitionStyleDefault, PartitionStyleHive} {
+		listPrefix := signalPrefix
+		if style == PartitionStyleHive {
+			listPrefix += "year="
+		}
+		keys := make([]string, 0)
+		_, err := r.storage.ListObjects(ctx, listPrefix, nil, func(obj storageObject) (bool, error) {
+			rel := strings.TrimPrefix(obj.Key, signalPrefix)
+			if style == PartitionStyleDefault && strings.HasPrefix(rel, "year=") {
+				// hive style partitions follow, they are listed next.
+				return false, nil
+			}
+			end, ok := r.partitioner.parseEnd(rel, style)
+			if !ok {
+				slog.DebugContext(ctx, "skip object not in partition", "key", obj.Key)
+				return true, nil
+			}
+			if end.After(result.Cutoff) {
+				return true, nil
+			}
+			keys = append(keys, obj.Key)
+			result.Objects++
+			result.Bytes += obj.Size
+			return true, nil
+		})
+		if err != nil {
+			return oops.Wrapf(err, "failed to list objects")
+		}
+		if dryRun {
+			continue
+		}
+		for _, key := range keys {
+			if err := r.storage.DeleteObject(ctx, key); err != nil {
+				return oops.Wrapf(err, "failed to delete object %q", key)
+			}
+			// the marker of the partition is written again, when signals of the partition are pushed again.
+			r.manifestMarked.Delete(key)
+		}
+	}
+	return nil
+}
+
+// RunPurgeLoop purges expired partitions at the purge interval, until ctx is done.
+func (r *ObjectSignalRepository) RunPurgeLoop(ctx context.Context) {
+	if r.retention.purgeInterval == 0 {
+		return
+	}
+	ticker := time.NewTicker(r.retention.purgeInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Purge(ctx, &PurgeOptions{}); err != nil {
+			slog.ErrorContext(ctx, "failed to purge expired signals", "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package oteleport
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mashiike/go-otlp-helper/otlp"
+	"github.com/samber/lo"
+	"github.com/samber/oops"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// replayPacingResolution is the time window of signals emitted together in paced replay.
+const replayPacingResolution = 10 * time.Millisecond
+
+// replayPacer controls the output rate of signals.
+// in paced mode, signals are emitted at the original cadence of their timestamps divided by speed,
+// anchored to the first emitted signal. maxSignalsPerSecond caps the rate in both modes.
+type replayPacer struct {
+	paced               bool
+	speed               float64
+	maxSignalsPerSecond int
+
+	baseSignalTime uint64
+	baseWallTime   time.Time
+	nextAllowed    time.Time
+}
+
+func newReplayPacer(opts *ClientReplayOptions) (*replayPacer, error) {
+	if !opts.Paced && opts.MaxSignalsPerSecond == 0 {
+		return nil, nil
+	}
+	if opts.Speed <= 0 {
+		return nil, oops.Errorf("speed must be positive")
+	}
+	if opts.MaxSignalsPerSecond < 0 {
+		return nil, oops.Errorf("max signals per second must be positive")
+	}
+	return &replayPacer{
+		paced:               opts.Paced,
+		speed:               opts.Speed,
+		maxSignalsPerSecond: opts.MaxSignalsPerSecond,
+	}, nil
+}
+
+// wait blocks until n signals with the timestamp can be emitted.
+func (p *replayPacer) wait(ctx context.Context, signalTimeUnixNano uint64, n int) error {
+	due := time.Now()
+	if p.paced && signalTimeUnixNano != 0 {
+		if p.baseWallTime.IsZero() {
+			p.baseSignalTime = signalTimeUnixNano
+			p.baseWallTime = due
+		}
+		elapsed := time.Duration(float64(int64(signalTimeUnixNano)-int64(p.baseSignalTime)) / p.speed)
+		due = p.baseWallTime.Add(elapsed)
+	}
+	if p.maxSignalsPerSecond > 0 {
+		if p.nextAllowed.After(due) {
+			due = p.nextAllowed
+		}
+		start := due
+		if now := time.Now(); now.After(start) {
+			start = now
+		}
+		p.nextAllowed = start.Add(time.Duration(n) * time.Second / time.Duration(p.maxSignalsPerSecond))
+	}
+	d := time.Until(due)
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+type replayUnit[T any] struct {
+	timeUnixNano uint64
+	data         T
+}
+
+func replay[T any](ctx context.Context, p *replayPacer, units []replayUnit[T], emit func([]T) error) error {
+	if p.paced {
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].timeUnixNano < units[j].timeUnixNano
+		})
+	}
+	window := uint64(float64(replayPacingResolution) * p.speed)
+	for i := 0; i < len(units); {
+		j := i + 1
+		for j < len(units) {
+			if p.maxSignalsPerSecond > 0 && j-i >= p.maxSignalsPerSecond {
+				break
+			}
+			if p.paced && units[j].timeUnixNano-units[i].timeUnixNano > window {
+				break
+			}
+			j++
+		}
+		if err := p.wait(ctx, units[i].timeUnixNano, j-i); err != nil {
+			return err
+		}
+		batch := lo.Map(units[i:j], func(u replayUnit[T], _ int) T {
+			return u.data
+		})
+		if err := emit(batch); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
+
+func (p *replayPacer) ReplayResourceSpans(ctx context.Context, resourceSpans []*tracepb.ResourceSpans, emit func([]*tracepb.ResourceSpans) error) error {
+	if p == nil {
+		return emit(resourceSpans)
+	}
+	units := lo.Map(otlp.SplitResourceSpans(resourceSpans), func(rs *tracepb.ResourceSpans, _ int) replayUnit[*tracepb.ResourceSpans] {
+		return replayUnit[*tracepb.ResourceSpans]{
+			timeUnixNano: rs.GetScopeSpans()[0].GetSpans()[0].GetStartTimeUnixNano(),
+			data:         rs,
+		}
+	})
+	return replay(ctx, p, units, func(batch []*tracepb.ResourceSpans) error {
+		return emit(otlp.AppendResourceSpans(nil, batch...))
+	})
+}
+
+func (p *replayPacer) ReplayResourceMetrics(ctx context.Context, resourceMetrics []*metricspb.ResourceMetrics, emit func([]*metricspb.ResourceMetrics) error) error {
+	if p == nil {
+		return emit(resourceMetrics)
+	}
+	units := lo.Map(otlp.SplitResourceMetrics(resourceMetrics), func(rm *metricspb.ResourceMetrics, _ int) replayUnit[*metricspb.ResourceMetrics] {
+		var t uint64
+		walkDataPointTimes([]*metricspb.ResourceMetrics{rm}, func(_ *uint64, timeUnixNano *uint64) {
+			t = earlier(t, *timeUnixNano)
+		}, nil)
+		return replayUnit[*metricspb.ResourceMetrics]{
+			timeUnixNano: t,
+			data:         rm,
+		}
+	})
+	return replay(ctx, p, units, func(batch []*metricspb.ResourceMetrics) error {
+		return emit(otlp.AppendResourceMetrics(nil, batch...))
+	})
+}
+
+func (p *replayPacer) ReplayResourceLogs(ctx context.Context, resourceLogs []*logspb.ResourceLogs, emit func([]*logspb.ResourceLogs) error) error {
+	if p == nil {
+		return emit(resourceLogs)
+	}
+	units := lo.Map(otlp.SplitResourceLogs(resourceLogs), func(rl *logspb.ResourceLogs, _ int) replayUnit[*logspb.ResourceLogs] {
+		logRecord := rl.GetScopeLogs()[0].GetLogRecords()[0]
+		t := logRecord.GetTimeUnixNano()
+		if t == 0 {
+			t = logRecord.GetObservedTimeUnixNano()
+		}
+		return replayUnit[*logspb.ResourceLogs]{
+			timeUnixNano: t,
+			data:         rl,
+		}
+	})
+	return replay(ctx, p, units, func(batch []*logspb.ResourceLogs) error {
+		return emit(otlp.AppendResourceLogs(nil, batch...))
+	})
+}
@@ -2,25 +2,23 @@ package oteleport
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	crand "crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math/rand"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/mashiike/go-otlp-helper/otlp"
 	oteleportpb "github.com/mashiike/oteleport/proto"
 	"github.com/samber/lo"
@@ -30,6 +28,7 @@ import (
 	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -40,50 +39,75 @@ type SignalRepository interface {
 	FetchTracesData(ctx context.Context, input *oteleportpb.FetchTracesDataRequest) (*oteleportpb.FetchTracesDataResponse, error)
 	FetchMetricsData(ctx context.Context, input *oteleportpb.FetchMetricsDataRequest) (*oteleportpb.FetchMetricsDataResponse, error)
 	FetchLogsData(ctx context.Context, input *oteleportpb.FetchLogsDataRequest) (*oteleportpb.FetchLogsDataResponse, error)
+	GetTrace(ctx context.Context, input *oteleportpb.GetTraceRequest) (*oteleportpb.GetTraceResponse, error)
+	Flush(ctx context.Context) error
+	RunFlushLoop(ctx context.Context)
+	Compact(ctx context.Context, opts *CompactOptions) (*CompactResult, error)
+	Purge(ctx context.Context, opts *PurgeOptions) ([]*PurgeResult, error)
+	RunPurgeLoop(ctx context.Context)
+	TimeRanges(ctx context.Context, signal string) ([]*TimeRange, error)
+	RebuildManifest(ctx context.Context, signal string) (int, error)
+	ForTenant(tenant string) SignalRepository
 }
 
-type S3SignalRepository struct {
-	bucketName          string
+type ObjectSignalRepository struct {
+	storage             objectStorage
 	objectPathPrefix    string
-	client              *s3.Client
-	gzip                bool
+	compression         *compressionCodec
 	flatten             bool
+	format              string
+	partitioner         *partitioner
+	tracesBuffer        *batchBuffer[*tracepb.ResourceSpans]
+	metricsBuffer       *batchBuffer[*metricspb.ResourceMetrics]
+	logsBuffer          *batchBuffer[*logspb.ResourceLogs]
+	flushInterval       time.Duration
+	prefetchWorkers     int
+	manifest            bool
+	manifestMarked      sync.Map
+	retention           StorageRetentionConfig
 	cursorEncryptionKey []byte
-	uploader            *manager.Uploader
-	downloader          *manager.Downloader
+	bufferBudget        *bufferBudget
+	cfg                 *StorageConfig
+	tenant              string
+	root                *ObjectSignalRepository
+	tenantsMu           sync.Mutex
+	tenants             map[string]*ObjectSignalRepository
 }
 
 func NewSignalRepository(cfg *StorageConfig) (SignalRepository, error) {
 	switch cfg.locationURL.Scheme {
 	case "s3":
-		return newS3SignalRepository(cfg), nil
+		return newObjectSignalRepository(cfg, newS3ObjectStorage(cfg), strings.TrimPrefix(cfg.locationURL.Path, "/"), newBufferBudget(cfg.Batch.MaxBufferBytes)), nil
+	case "file":
+		return newObjectSignalRepository(cfg, newFileObjectStorage(cfg), "", newBufferBudget(cfg.Batch.MaxBufferBytes)), nil
+	case "memory":
+		return newObjectSignalRepository(cfg, newMemoryObjectStorage(cfg), strings.TrimPrefix(cfg.locationURL.Path, "/"), newBufferBudget(cfg.Batch.MaxBufferBytes)), nil
 	default:
 		return nil, oops.Errorf("unsupported location scheme %s", cfg.locationURL.Scheme)
 	}
 }
 
-func newS3SignalRepository(cfg *StorageConfig) *S3SignalRepository {
-	s3Opts := []func(*s3.Options){}
-	if cfg.AWS.Endpoint != "" {
-		s3Opts = append(s3Opts, func(o *s3.Options) {
-			o.BaseEndpoint = aws.String(cfg.AWS.Endpoint)
-		})
-	}
-	if cfg.AWS.UseS3PathStyle {
-		s3Opts = append(s3Opts, func(o *s3.Options) {
-			o.UsePathStyle = true
-		})
-	}
-	client := s3.NewFromConfig(cfg.AWS.awsConfig, s3Opts...)
-	return &S3SignalRepository{
+// newObjectSignalRepository returns the repository, buffers of the repository charge buffered bytes to the budget.
+// repositories of tenants share the budget of the top level, so that the memory is bounded regardless of the number of tenants.
+func newObjectSignalRepository(cfg *StorageConfig, storage objectStorage, objectPathPrefix string, budget *bufferBudget) *ObjectSignalRepository {
+	return &ObjectSignalRepository{
+		storage:             storage,
+		objectPathPrefix:    objectPathPrefix,
 		cursorEncryptionKey: adjustKey(cfg.CursorEncryptionKey, 32),
-		gzip:                cfg.GZip != nil && *cfg.GZip,
+		compression:         compressionCodecByName(cfg.Compression),
 		flatten:             cfg.Flatten != nil && *cfg.Flatten,
-		bucketName:          cfg.locationURL.Host,
-		objectPathPrefix:    strings.TrimPrefix(cfg.locationURL.Path, "/"),
-		client:              client,
-		uploader:            manager.NewUploader(client),
-		downloader:          manager.NewDownloader(client),
+		format:              cfg.Format,
+		partitioner:         newPartitioner(cfg),
+		tracesBuffer:        newBatchBuffer(&cfg.Batch, budget, otlp.AppendResourceSpans, otlp.TotalSpans),
+		metricsBuffer:       newBatchBuffer(&cfg.Batch, budget, otlp.AppendResourceMetrics, otlp.TotalDataPoints),
+		logsBuffer:          newBatchBuffer(&cfg.Batch, budget, otlp.AppendResourceLogs, otlp.TotalLogRecords),
+		flushInterval:       max(cfg.Batch.maxAge/4, 100*time.Millisecond),
+		prefetchWorkers:     max(cfg.PrefetchWorkers, 1),
+		manifest:            cfg.Manifest.Enable != nil && *cfg.Manifest.Enable,
+		retention:           cfg.Retention,
+		bufferBudget:        budget,
+		cfg:                 cfg,
+		tenants:             make(map[string]*ObjectSignalRepository),
 	}
 }
 
@@ -98,242 +122,551 @@ func RandomString(n int) string {
 	return string(b)
 }
 
-const (
-	partitionForamt = "2006/01/02/15"
-)
-
-func (r *S3SignalRepository) PushTracesData(ctx context.Context, data *tracepb.TracesData) error {
+func (r *ObjectSignalRepository) PushTracesData(ctx context.Context, data *tracepb.TracesData) error {
 	partitionBy := otlp.PartitionResourceSpans(data.GetResourceSpans(), func(rs *tracepb.ResourceSpans) string {
-		if str := otlp.PartitionBySpanStartTime(partitionForamt, time.Local)(rs); str != "" {
-			return str
-		}
-		if str := otlp.PartitionBySpanEndTime(partitionForamt, time.Local)(rs); str != "" {
-			return str
+		partition := r.partitioner.format(time.Now())
+		if str := otlp.PartitionBySpanStartTime(r.partitioner.layout(), r.partitioner.location)(rs); str != "" {
+			partition = str
+		} else if str := otlp.PartitionBySpanEndTime(r.partitioner.layout(), r.partitioner.location)(rs); str != "" {
+			partition = str
 		}
-		return time.Now().Format(partitionForamt)
+		return r.partitioner.withResource(partition, rs.GetResource())
 	})
 	for partition, spans := range partitionBy {
-		var builder strings.Builder
-		enc := otlp.NewJSONEncoder(&builder)
-		var protoData []protoreflect.ProtoMessage
-		if r.flatten {
-			protoData = lo.Map(oteleportpb.ConvertToFlattenSpans(spans), func(s *oteleportpb.FlattenSpan, _ int) protoreflect.ProtoMessage {
-				return s
-			})
-		} else {
-			protoData = []protoreflect.ProtoMessage{
-				&tracepb.TracesData{
-					ResourceSpans: spans,
-				},
+		if r.tracesBuffer != nil {
+			if err := pushBatch(ctx, r.tracesBuffer, partition, spans, r.writeResourceSpans); err != nil {
+				return err
 			}
+			continue
 		}
-		for i, d := range protoData {
-			if i > 0 {
-				builder.WriteString("\n")
-			}
-			if err := enc.Encode(d); err != nil {
-				return oops.Wrapf(err, "failed to encode json")
-			}
-		}
-		spansCount := otlp.TotalSpans(spans)
-		slog.DebugContext(ctx, "push traces data", "partition", partition, "spans", spansCount)
-		objectKeySuffix := fmt.Sprintf("traces/%s/spans-%s-%s.json", partition, time.Now().Format("20060102150405"), RandomString(8))
-		if err := r.putObject(ctx, objectKeySuffix, strings.NewReader(builder.String())); err != nil {
-			return oops.Wrapf(err, "failed to put object")
+		if err := r.writeResourceSpans(ctx, partition, spans); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-var zeroTimeStr = time.Unix(0, 0).In(time.Local).Format(partitionForamt)
+func (r *ObjectSignalRepository) writeResourceSpans(ctx context.Context, partition string, spans []*tracepb.ResourceSpans) error {
+	body, ext, err := r.encodeResourceSpans(spans)
+	if err != nil {
+		return err
+	}
+	spansCount := otlp.TotalSpans(spans)
+	slog.DebugContext(ctx, "push traces data", "partition", partition, "spans", spansCount)
+	objectName := fmt.Sprintf("%s-%s", time.Now().Format("20060102150405"), RandomString(8))
+	objectKeySuffix := fmt.Sprintf("traces/%s/spans-%s.%s", partition, objectName, ext)
+	objKey, err := r.putObject(ctx, objectKeySuffix, bytes.NewReader(body), spansCount)
+	if err != nil {
+		return oops.Wrapf(err, "failed to put object")
+	}
+	// the index is written after the span object, so that the index never refers to a missing object.
+	indexKeySuffix := fmt.Sprintf("traces-index/%s/index-%s.json", partition, objectName)
+	if err := r.putTraceIndex(ctx, indexKeySuffix, newTraceIndexEntries(objKey, spans)); err != nil {
+		return oops.Wrapf(err, "failed to put trace index")
+	}
+	return r.markPartition(ctx, "traces", partition)
+}
 
-func (r *S3SignalRepository) PushMetricsData(ctx context.Context, data *metricspb.MetricsData) error {
+func (r *ObjectSignalRepository) PushMetricsData(ctx context.Context, data *metricspb.MetricsData) error {
+	zeroTimeStr := r.partitioner.format(time.Unix(0, 0))
 	partitionBy := otlp.PartitionResourceMetrics(data.GetResourceMetrics(), func(rm *metricspb.ResourceMetrics) string {
-		if str := otlp.PartitionByMetricStartTime(partitionForamt, time.Local)(rm); str != "" && str != zeroTimeStr {
-			return str
-		}
-		if str := otlp.PartitionByMetricTime(partitionForamt, time.Local)(rm); str != "" && str != zeroTimeStr {
-			return str
+		partition := r.partitioner.format(time.Now())
+		if str := otlp.PartitionByMetricStartTime(r.partitioner.layout(), r.partitioner.location)(rm); str != "" && str != zeroTimeStr {
+			partition = str
+		} else if str := otlp.PartitionByMetricTime(r.partitioner.layout(), r.partitioner.location)(rm); str != "" && str != zeroTimeStr {
+			partition = str
 		}
-		return time.Now().Format(partitionForamt)
+		return r.partitioner.withResource(partition, rm.GetResource())
 	})
 	for partition, metrics := range partitionBy {
-		var builder strings.Builder
-		enc := otlp.NewJSONEncoder(&builder)
-		var protoData []protoreflect.ProtoMessage
-		if r.flatten {
-			protoData = lo.Map(oteleportpb.ConvertToFlattenDataPoints(metrics), func(d *oteleportpb.FlattenDataPoint, _ int) protoreflect.ProtoMessage {
-				return d
-			})
-		} else {
-			protoData = []protoreflect.ProtoMessage{
-				&metricspb.MetricsData{
-					ResourceMetrics: metrics,
-				},
+		if r.metricsBuffer != nil {
+			if err := pushBatch(ctx, r.metricsBuffer, partition, metrics, r.writeResourceMetrics); err != nil {
+				return err
 			}
+			continue
 		}
-		for i, d := range protoData {
-			if i > 0 {
-				builder.WriteString("\n")
-			}
-			if err := enc.Encode(d); err != nil {
-				return oops.Wrapf(err, "failed to encode json")
-			}
-		}
-		metricsCount := otlp.TotalDataPoints(metrics)
-		slog.DebugContext(ctx, "push metrics data", "partition", partition, "metrics", metricsCount)
-		objectKeySuffix := fmt.Sprintf("metrics/%s/data-points-%s-%s.json", partition, time.Now().Format("20060102150405"), RandomString(8))
-		if err := r.putObject(ctx, objectKeySuffix, strings.NewReader(builder.String())); err != nil {
-			return oops.Wrapf(err, "failed to put object")
+		if err := r.writeResourceMetrics(ctx, partition, metrics); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (r *S3SignalRepository) PushLogsData(ctx context.Context, data *logspb.LogsData) error {
+func (r *ObjectSignalRepository) writeResourceMetrics(ctx context.Context, partition string, metrics []*metricspb.ResourceMetrics) error {
+	body, ext, err := r.encodeResourceMetrics(metrics)
+	if err != nil {
+		return err
+	}
+	metricsCount := otlp.TotalDataPoints(metrics)
+	slog.DebugContext(ctx, "push metrics data", "partition", partition, "metrics", metricsCount)
+	objectKeySuffix := fmt.Sprintf("metrics/%s/data-points-%s-%s.%s", partition, time.Now().Format("20060102150405"), RandomString(8), ext)
+	if _, err := r.putObject(ctx, objectKeySuffix, bytes.NewReader(body), metricsCount); err != nil {
+		return oops.Wrapf(err, "failed to put object")
+	}
+	return r.markPartition(ctx, "metrics", partition)
+}
+
+func (r *ObjectSignalRepository) PushLogsData(ctx context.Context, data *logspb.LogsData) error {
 	partitionBy := otlp.PartitionResourceLogs(data.GetResourceLogs(), func(rl *logspb.ResourceLogs) string {
-		if str := otlp.PartitionByLogTime(partitionForamt, time.Local)(rl); str != "" {
-			return str
+		partition := r.partitioner.format(time.Now())
+		if str := otlp.PartitionByLogTime(r.partitioner.layout(), r.partitioner.location)(rl); str != "" {
+			partition = str
+		} else if str := otlp.PartitionByLogObservedTime(r.partitioner.layout(), r.partitioner.location)(rl); str != "" {
+			partition = str
 		}
-		if str := otlp.PartitionByLogObservedTime(partitionForamt, time.Local)(rl); str != "" {
-			return str
-		}
-		return time.Now().Format(partitionForamt)
+		return r.partitioner.withResource(partition, rl.GetResource())
 	})
 	for partition, logs := range partitionBy {
-		var builder strings.Builder
-		enc := otlp.NewJSONEncoder(&builder)
-		var protoData []protoreflect.ProtoMessage
-		if r.flatten {
-			protoData = lo.Map(oteleportpb.ConvertToFlattenLogRecords(logs), func(r *oteleportpb.FlattenLogRecord, _ int) protoreflect.ProtoMessage {
-				return r
-			})
-		} else {
-			protoData = []protoreflect.ProtoMessage{
-				&logspb.LogsData{
-					ResourceLogs: logs,
-				},
+		if r.logsBuffer != nil {
+			if err := pushBatch(ctx, r.logsBuffer, partition, logs, r.writeResourceLogs); err != nil {
+				return err
 			}
+			continue
 		}
-		for i, d := range protoData {
-			if i > 0 {
-				builder.WriteString("\n")
-			}
-			if err := enc.Encode(d); err != nil {
-				return oops.Wrapf(err, "failed to encode json")
-			}
-		}
-		logsCount := otlp.TotalLogRecords(logs)
-		slog.DebugContext(ctx, "push logs data", "partition", partition, "logs", logsCount)
-		objectKeySuffix := fmt.Sprintf("logs/%s/records-%s-%s.json", partition, time.Now().Format("20060102150405"), RandomString(8))
-		if err := r.putObject(ctx, objectKeySuffix, strings.NewReader(builder.String())); err != nil {
-			return oops.Wrapf(err, "failed to put object")
+		if err := r.writeResourceLogs(ctx, partition, logs); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (r *S3SignalRepository) putObject(ctx context.Context, objectKeySuffix string, body io.Reader) error {
-	objKey := filepath.Join(r.objectPathPrefix, objectKeySuffix)
-	var contentEncoding *string
-	if r.gzip {
-		var buf bytes.Buffer
-		gzipWriter := gzip.NewWriter(&buf)
-		if _, err := io.Copy(gzipWriter, body); err != nil {
-			return oops.Wrapf(err, "failed to write gzip")
-		}
-		if err := gzipWriter.Close(); err != nil {
-			return oops.Wrapf(err, "failed to close gzip")
-		}
-		body = &buf
-		objKey += ".gz"
-		contentEncoding = aws.String("gzip")
-	}
-	output, err := r.uploader.Upload(ctx, &s3.PutObjectInput{
-		Bucket:          aws.String(r.bucketName),
-		Key:             aws.String(objKey),
-		Body:            body,
-		ContentType:     aws.String("application/json"),
-		ContentEncoding: contentEncoding,
-	})
+func (r *ObjectSignalRepository) writeResourceLogs(ctx context.Context, partition string, logs []*logspb.ResourceLogs) error {
+	body, ext, err := r.encodeResourceLogs(logs)
 	if err != nil {
+		return err
+	}
+	logsCount := otlp.TotalLogRecords(logs)
+	slog.DebugContext(ctx, "push logs data", "partition", partition, "logs", logsCount)
+	objectKeySuffix := fmt.Sprintf("logs/%s/records-%s-%s.%s", partition, time.Now().Format("20060102150405"), RandomString(8), ext)
+	if _, err := r.putObject(ctx, objectKeySuffix, bytes.NewReader(body), logsCount); err != nil {
 		return oops.Wrapf(err, "failed to put object")
 	}
-	slog.InfoContext(ctx, "put object", "s3_url", output.Location, "etag", output.ETag, "version_id", output.VersionID)
+	return r.markPartition(ctx, "logs", partition)
+}
+
+// Flush writes all buffered signals.
+func (r *ObjectSignalRepository) Flush(ctx context.Context) error {
+	return r.flush(ctx, true)
+}
+
+// RunFlushLoop writes buffered signals older than the max age periodically, until ctx is done.
+// buffered signals at the end are not written, call Flush after stopping to receive signals.
+func (r *ObjectSignalRepository) RunFlushLoop(ctx context.Context) {
+	if r.tracesBuffer == nil && r.metricsBuffer == nil && r.logsBuffer == nil {
+		return
+	}
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.flush(ctx, false); err != nil {
+				slog.ErrorContext(ctx, "failed to flush buffered signals", "error", err.Error())
+			}
+		}
+	}
+}
+
+func (r *ObjectSignalRepository) flush(ctx context.Context, all bool) error {
+	var errs []error
+	for _, t := range r.allRepositories()[1:] {
+		errs = append(errs, t.flush(ctx, all))
+	}
+	if r.tracesBuffer != nil {
+		for _, batch := range r.tracesBuffer.take(all) {
+			errs = append(errs, writeBatch(ctx, r.tracesBuffer, batch, r.writeResourceSpans))
+		}
+	}
+	if r.metricsBuffer != nil {
+		for _, batch := range r.metricsBuffer.take(all) {
+			errs = append(errs, writeBatch(ctx, r.metricsBuffer, batch, r.writeResourceMetrics))
+		}
+	}
+	if r.logsBuffer != nil {
+		for _, batch := range r.logsBuffer.take(all) {
+			errs = append(errs, writeBatch(ctx, r.logsBuffer, batch, r.writeResourceLogs))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pushBatch adds data to the buffer, and writes the batch when it gets full.
+// when the full batch fails to write, Unavailable is returned so that the exporter retries data,
+// and only the batch buffered before data is put back to the buffer.
+func pushBatch[T proto.Message](ctx context.Context, buf *batchBuffer[T], partition string, data []T, write func(context.Context, string, []T) error) error {
+	batch, prev, err := buf.add(partition, data)
+	if err != nil {
+		return err
+	}
+	if batch == nil {
+		return nil
+	}
+	slog.DebugContext(ctx, "write batch", "partition", batch.partition, "records", batch.records, "bytes", batch.bytes)
+	if err := write(ctx, batch.partition, batch.data); err != nil {
+		slog.ErrorContext(ctx, "failed to write batch", "partition", batch.partition, "records", batch.records, "error", err.Error())
+		if prev != nil {
+			requeueBatch(ctx, buf, prev)
+		}
+		return status.Error(codes.Unavailable, "failed to write signals, retry later")
+	}
+	return nil
+}
+
+// writeBatch writes the batch taken by flush, the batch failed to write is put back to the buffer and retried by the next flush.
+func writeBatch[T proto.Message](ctx context.Context, buf *batchBuffer[T], batch *signalBatch[T], write func(context.Context, string, []T) error) error {
+	slog.DebugContext(ctx, "write batch", "partition", batch.partition, "records", batch.records, "bytes", batch.bytes)
+	if err := write(ctx, batch.partition, batch.data); err != nil {
+		slog.ErrorContext(ctx, "failed to write batch, retry later", "partition", batch.partition, "records", batch.records, "error", err.Error())
+		requeueBatch(ctx, buf, batch)
+		return oops.Wrapf(err, "failed to write batch")
+	}
 	return nil
 }
 
-func (r *S3SignalRepository) walkObjects(
+func requeueBatch[T proto.Message](ctx context.Context, buf *batchBuffer[T], batch *signalBatch[T]) {
+	if !buf.requeue(batch) {
+		slog.ErrorContext(ctx, "drop batch failed to write, the write buffer is full", "partition", batch.partition, "records", batch.records, "bytes", batch.bytes)
+	}
+}
+
+// encodeResourceSpans encodes spans in the storage format, and returns the body and the object key extension.
+func (r *ObjectSignalRepository) encodeResourceSpans(spans []*tracepb.ResourceSpans) ([]byte, string, error) {
+	if r.format == StorageFormatParquet {
+		body, err := encodeParquetSpans(oteleportpb.ConvertToFlattenSpans(spans))
+		if err != nil {
+			return nil, "", oops.Wrapf(err, "failed to encode parquet")
+		}
+		return body, "parquet", nil
+	}
+	if r.format == StorageFormatProtobuf {
+		if r.flatten {
+			body, err := encodeDelimited(oteleportpb.ConvertToFlattenSpans(spans))
+			return body, flattenProtobufObjectExt, err
+		}
+		body, err := encodeDelimited([]*tracepb.TracesData{{ResourceSpans: spans}})
+		return body, protobufObjectExt, err
+	}
+	var protoData []protoreflect.ProtoMessage
+	if r.flatten {
+		protoData = lo.Map(oteleportpb.ConvertToFlattenSpans(spans), func(s *oteleportpb.FlattenSpan, _ int) protoreflect.ProtoMessage {
+			return s
+		})
+	} else {
+		protoData = []protoreflect.ProtoMessage{
+			&tracepb.TracesData{
+				ResourceSpans: spans,
+			},
+		}
+	}
+	body, err := encodeJSONLines(protoData)
+	if r.flatten {
+		return body, flattenJSONObjectExt, err
+	}
+	return body, "json", err
+}
+
+func (r *ObjectSignalRepository) encodeResourceMetrics(metrics []*metricspb.ResourceMetrics) ([]byte, string, error) {
+	if r.format == StorageFormatParquet {
+		body, err := encodeParquetDataPoints(oteleportpb.ConvertToFlattenDataPoints(metrics))
+		if err != nil {
+			return nil, "", oops.Wrapf(err, "failed to encode parquet")
+		}
+		return body, "parquet", nil
+	}
+	if r.format == StorageFormatProtobuf {
+		if r.flatten {
+			body, err := encodeDelimited(oteleportpb.ConvertToFlattenDataPoints(metrics))
+			return body, flattenProtobufObjectExt, err
+		}
+		body, err := encodeDelimited([]*metricspb.MetricsData{{ResourceMetrics: metrics}})
+		return body, protobufObjectExt, err
+	}
+	var protoData []protoreflect.ProtoMessage
+	if r.flatten {
+		protoData = lo.Map(oteleportpb.ConvertToFlattenDataPoints(metrics), func(d *oteleportpb.FlattenDataPoint, _ int) protoreflect.ProtoMessage {
+			return d
+		})
+	} else {
+		protoData = []protoreflect.ProtoMessage{
+			&metricspb.MetricsData{
+				ResourceMetrics: metrics,
+			},
+		}
+	}
+	body, err := encodeJSONLines(protoData)
+	if r.flatten {
+		return body, flattenJSONObjectExt, err
+	}
+	return body, "json", err
+}
+
+func (r *ObjectSignalRepository) encodeResourceLogs(logs []*logspb.ResourceLogs) ([]byte, string, error) {
+	if r.format == StorageFormatParquet {
+		body, err := encodeParquetLogRecords(oteleportpb.ConvertToFlattenLogRecords(logs))
+		if err != nil {
+			return nil, "", oops.Wrapf(err, "failed to encode parquet")
+		}
+		return body, "parquet", nil
+	}
+	if r.format == StorageFormatProtobuf {
+		if r.flatten {
+			body, err := encodeDelimited(oteleportpb.ConvertToFlattenLogRecords(logs))
+			return body, flattenProtobufObjectExt, err
+		}
+		body, err := encodeDelimited([]*logspb.LogsData{{ResourceLogs: logs}})
+		return body, protobufObjectExt, err
+	}
+	var protoData []protoreflect.ProtoMessage
+	if r.flatten {
+		protoData = lo.Map(oteleportpb.ConvertToFlattenLogRecords(logs), func(r *oteleportpb.FlattenLogRecord, _ int) protoreflect.ProtoMessage {
+			return r
+		})
+	} else {
+		protoData = []protoreflect.ProtoMessage{
+			&logspb.LogsData{
+				ResourceLogs: logs,
+			},
+		}
+	}
+	body, err := encodeJSONLines(protoData)
+	if r.flatten {
+		return body, flattenJSONObjectExt, err
+	}
+	return body, "json", err
+}
+
+func encodeJSONLines(protoData []protoreflect.ProtoMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := otlp.NewJSONEncoder(&buf)
+	for i, d := range protoData {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if err := enc.Encode(d); err != nil {
+			return nil, oops.Wrapf(err, "failed to encode json")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// putObject puts the object and returns the object key actually written.
+func (r *ObjectSignalRepository) putObject(ctx context.Context, objectKeySuffix string, body io.Reader, signalCount int) (string, error) {
+	objKey := filepath.Join(r.objectPathPrefix, objectKeySuffix)
+	opts := &putObjectOptions{
+		ContentType: "application/json",
+		SignalCount: signalCount,
+	}
+	isParquet := strings.HasSuffix(objKey, ".parquet")
+	if isParquet {
+		// parquet is compressed per column chunk, so the object is not compressed.
+		opts.ContentType = "application/vnd.apache.parquet"
+	}
+	if strings.HasSuffix(objKey, "."+protobufObjectExt) {
+		opts.ContentType = "application/x-protobuf"
+	}
+	if r.compression != nil && !isParquet {
+		buf, err := r.compression.compress(body)
+		if err != nil {
+			return "", err
+		}
+		body = buf
+		objKey += r.compression.suffix
+		opts.ContentEncoding = r.compression.contentEncoding
+	}
+	if err := r.storage.PutObject(ctx, objKey, body, opts); err != nil {
+		return "", oops.Wrapf(err, "failed to put object")
+	}
+	return objKey, nil
+}
+
+// listPartitionObjects calls f for each object in the partitions between startTime and endTime, in the order of the cursor.
+// startAfter is the object key of the cursor, objects up to the key are skipped.
+// when resourcePartition is not empty, only objects of the resource partition and objects without resource partitions are listed.
+func (r *ObjectSignalRepository) listPartitionObjects(
 	ctx context.Context,
 	startTime time.Time, endTime time.Time,
 	startAfter *string,
-	getObjectKeyPrefixFunc func(time.Time) string,
-	f func(context.Context, time.Time, types.Object) (bool, error),
+	signal string,
+	resourcePartition string,
+	f func(context.Context, time.Time, storageObject) (bool, error),
 ) (bool, error) {
-	currentTime := startTime.Truncate(time.Hour)
+	var partitions map[string]bool
+	if r.manifest {
+		var err error
+		if partitions, err = r.loadManifest(ctx, signal, startTime, endTime); err != nil {
+			return false, err
+		}
+		if len(partitions) == 0 {
+			// no markers in the time range, like objects stored before enabling the manifest, so all partitions are listed.
+			slog.DebugContext(ctx, "no manifest in the time range, list all partitions", "signal", signal, "start_time", startTime, "end_time", endTime)
+			partitions = nil
+		}
+	}
+	currentTime := r.partitioner.truncate(startTime)
+	var lastObjectKeyPrefixes []string
 	slog.DebugContext(ctx, "start walk objects", "start_time", startTime, "end_time", endTime, "current_time", currentTime, "is_equal", currentTime.Equal(endTime), "is_before", currentTime.Before(endTime))
 	for currentTime.Before(endTime) || currentTime.Equal(endTime) {
-		objectKeyPrefix := getObjectKeyPrefixFunc(currentTime)
-		slog.DebugContext(ctx, "list objects", "prefix", objectKeyPrefix, "start_after", startAfter)
-		paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
-			Bucket:     aws.String(r.bucketName),
-			Prefix:     aws.String(objectKeyPrefix),
-			StartAfter: startAfter,
-		})
-		for paginator.HasMorePages() {
-			page, err := paginator.NextPage(ctx)
+		objectKeyPrefixes := r.objectKeyPrefixes(signal, resourcePartition)(currentTime)
+		if slices.Equal(objectKeyPrefixes, lastObjectKeyPrefixes) {
+			// the same partition appears twice when the clock goes back, like at the end of DST.
+			currentTime = r.partitioner.next(currentTime)
+			continue
+		}
+		lastObjectKeyPrefixes = objectKeyPrefixes
+		if partitions != nil && !partitions[r.manifestKey(signal, currentTime)] {
+			slog.DebugContext(ctx, "skip empty partition", "signal", signal, "current_time", currentTime)
+			currentTime = r.partitioner.next(currentTime)
+			continue
+		}
+		if startAfter != nil {
+			// prefixes before the one of the cursor object are already read.
+			if i := slices.IndexFunc(objectKeyPrefixes, func(prefix string) bool {
+				return strings.HasPrefix(*startAfter, prefix)
+			}); i > 0 {
+				objectKeyPrefixes = objectKeyPrefixes[i:]
+			}
+		}
+		for _, objectKeyPrefix := range objectKeyPrefixes {
+			var after *string
+			if startAfter != nil && strings.HasPrefix(*startAfter, objectKeyPrefix) {
+				after = startAfter
+			}
+			slog.DebugContext(ctx, "list objects", "prefix", objectKeyPrefix, "start_after", after)
+			t := currentTime
+			ok, err := r.storage.ListObjects(ctx, objectKeyPrefix, after, func(obj storageObject) (bool, error) {
+				return f(ctx, t, obj)
+			})
 			if err != nil {
-				return false, oops.Wrapf(err, "failed to list objects")
+				return false, err
 			}
-			for _, obj := range page.Contents {
-				ok, err := f(ctx, currentTime, obj)
-				if err != nil {
-					return false, err
-				}
-				if !ok {
-					return false, nil
-				}
+			if !ok {
+				return false, nil
 			}
 		}
-		currentTime = currentTime.Add(time.Hour)
+		currentTime = r.partitioner.next(currentTime)
 		slog.DebugContext(ctx, "next walk", "start_time", startTime, "end_time", endTime, "current_time", currentTime, "is_equal", currentTime.Equal(endTime), "is_before", currentTime.Before(endTime))
 	}
 	slog.DebugContext(ctx, "end walk objects", "start_time", startTime, "end_time", endTime, "current_time", currentTime)
 	return true, nil
 }
 
-func (r *S3SignalRepository) getObjectBody(ctx context.Context, obj types.Object) ([]byte, error) {
-	var buf = make([]byte, 1024*1024*5) //5MB
-	w := manager.NewWriteAtBuffer(buf)
-	n, err := r.downloader.Download(ctx, w, &s3.GetObjectInput{
-		Bucket: aws.String(r.bucketName),
-		Key:    aws.String(*obj.Key),
-	})
-	if err != nil {
-		return nil, oops.Wrapf(err, "failed to get object")
+// walkedObject is an object listed by walkObjects, and the data loaded in background.
+type walkedObject[T any] struct {
+	t       time.Time
+	obj     storageObject
+	skipped bool
+	done    chan struct{}
+	data    T
+	err     error
+}
+
+// walkObjects calls f for each object in the partitions between startTime and endTime, in the order of the cursor.
+// data of objects are loaded by load concurrently with up to prefetch workers ahead of f, so f is called in the same order as listed.
+// skip is called in the listing order before loading, and skipped objects are passed to f without data.
+func walkObjects[T any](
+	ctx context.Context,
+	r *ObjectSignalRepository,
+	startTime time.Time, endTime time.Time,
+	startAfter *string,
+	signal string,
+	resourcePartition string,
+	skip func(context.Context, time.Time, storageObject) (bool, error),
+	load func(context.Context, storageObject) (T, error),
+	f func(ctx context.Context, t time.Time, obj storageObject, data T, skipped bool) (bool, error),
+) (bool, error) {
+	walkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	queue := make(chan *walkedObject[T], r.prefetchWorkers)
+	sem := make(chan struct{}, r.prefetchWorkers)
+	var wg sync.WaitGroup
+	var listOK bool
+	var listErr error
+	go func() {
+		defer close(queue)
+		listOK, listErr = r.listPartitionObjects(walkCtx, startTime, endTime, startAfter, signal, resourcePartition, func(ctx context.Context, t time.Time, obj storageObject) (bool, error) {
+			o := &walkedObject[T]{t: t, obj: obj, done: make(chan struct{})}
+			if skip != nil {
+				var err error
+				if o.skipped, err = skip(ctx, t, obj); err != nil {
+					return false, err
+				}
+			}
+			if o.skipped {
+				close(o.done)
+			} else {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return false, ctx.Err()
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-sem }()
+					defer close(o.done)
+					o.data, o.err = load(ctx, obj)
+				}()
+			}
+			select {
+			case queue <- o:
+				return true, nil
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		})
+	}()
+	var stopped bool
+	var err error
+	for o := range queue {
+		<-o.done
+		if o.err != nil {
+			err = o.err
+			break
+		}
+		var ok bool
+		if ok, err = f(ctx, o.t, o.obj, o.data, o.skipped); err != nil || !ok {
+			stopped = true
+			break
+		}
+	}
+	// stop listing and loading ahead, and wait for them.
+	cancel()
+	for range queue {
 	}
-	body := w.Bytes()[:n]
-	gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+	wg.Wait()
 	if err != nil {
-		if err == gzip.ErrHeader {
-			return body, nil
-		}
-		return nil, oops.Wrapf(err, "failed to create gzip reader")
+		return false, err
+	}
+	if stopped {
+		return false, nil
 	}
-	body, err = io.ReadAll(gzipReader)
+	if listErr != nil {
+		return false, listErr
+	}
+	return listOK, nil
+}
+
+func (r *ObjectSignalRepository) getObjectBody(ctx context.Context, obj storageObject) ([]byte, error) {
+	body, err := r.storage.GetObject(ctx, obj.Key)
 	if err != nil {
-		return nil, oops.Wrapf(err, "failed to read gzip")
+		return nil, oops.Wrapf(err, "failed to get object")
+	}
+	codec := detectCompressionCodec(obj.Key, body)
+	if codec == nil {
+		return body, nil
 	}
-	return body, nil
+	return codec.decompress(body)
 }
 
-type s3Cursor struct {
+type objectCursor struct {
 	CurrentTime      time.Time `json:"ct"`
 	CurrentObjectKey *string   `json:"ck"`
 	Offset           int       `json:"o"`
 }
 
-func (c *s3Cursor) encrypt(key []byte) (string, error) {
+func (c *objectCursor) encrypt(key []byte) (string, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", oops.Wrapf(err, "failed to create cipher")
@@ -354,7 +687,7 @@ func (c *s3Cursor) encrypt(key []byte) (string, error) {
 	return base64.URLEncoding.EncodeToString(ciphertext), nil
 }
 
-func (c *s3Cursor) decrypt(encryptedCursor string, key []byte) error {
+func (c *objectCursor) decrypt(encryptedCursor string, key []byte) error {
 	ciphertext, err := base64.URLEncoding.DecodeString(encryptedCursor)
 	if err != nil {
 		return oops.Wrapf(err, "failed to decode base64")
@@ -389,16 +722,21 @@ func adjustKey(key []byte, size int) []byte {
 	return paddedKey
 }
 
-func (r *S3SignalRepository) FetchTracesData(ctx context.Context, input *oteleportpb.FetchTracesDataRequest) (*oteleportpb.FetchTracesDataResponse, error) {
+func (r *ObjectSignalRepository) FetchTracesData(ctx context.Context, input *oteleportpb.FetchTracesDataRequest) (*oteleportpb.FetchTracesDataResponse, error) {
 	startTime, endTime, limit, err := validateRequest(input.GetStartTimeUnixNano(), input.GetEndTimeUnixNano(), input.GetLimit())
 	if err != nil {
 		return nil, err
 	}
 	cursor := input.GetCursor()
 	slog.InfoContext(ctx, "fetch traces data", "start_time", startTime, "end_time", endTime, "cursor", cursor, "limit", limit)
+	lookup, err := r.newTraceIndexLookup(input.GetFilter().GetTraceIds(), startTime, endTime)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	resp := &oteleportpb.FetchTracesDataResponse{}
 	num := 0
-	cursorObj := &s3Cursor{}
+	cursorObj := &objectCursor{}
+	walkStartTime := startTime
 	if cursor != "" {
 		if err := cursorObj.decrypt(cursor, r.cursorEncryptionKey); err != nil {
 			errID := RandomString(8)
@@ -406,62 +744,58 @@ func (r *S3SignalRepository) FetchTracesData(ctx context.Context, input *otelepo
 			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid cursor: err_id=%s", errID))
 		}
 		if !cursorObj.CurrentTime.IsZero() {
-			startTime = cursorObj.CurrentTime
+			walkStartTime = cursorObj.CurrentTime
 		}
-		slog.DebugContext(ctx, "cursor", "current_time", cursorObj.CurrentTime, "current_object_key", cursorObj.CurrentObjectKey, "offset", cursorObj.Offset, "start_time", startTime)
+		slog.DebugContext(ctx, "cursor", "current_time", cursorObj.CurrentTime, "current_object_key", cursorObj.CurrentObjectKey, "offset", cursorObj.Offset, "start_time", walkStartTime)
 	}
-	noMore, err := r.walkObjects(
+	noMore, err := walkObjects(
 		ctx,
-		startTime,
+		r,
+		walkStartTime,
 		endTime,
 		cursorObj.CurrentObjectKey,
-		func(t time.Time) string {
-			key := fmt.Sprintf("traces/%s/", t.Format(partitionForamt))
-			if r.objectPathPrefix != "" {
-				key = filepath.Join(r.objectPathPrefix, key)
-			}
-			return key
+		"traces",
+		r.partitioner.filterResourcePartition(input.GetFilter().GetResourceAttributes()),
+		func(ctx context.Context, t time.Time, obj storageObject) (bool, error) {
+			ok, err := lookup.mayContain(ctx, t, obj.Key)
+			return !ok, err
 		},
-		func(ctx context.Context, t time.Time, obj types.Object) (bool, error) {
-			slog.DebugContext(ctx, "fetch object", "key", *obj.Key)
-			body, err := r.getObjectBody(ctx, obj)
-			if err != nil {
-				return false, oops.Wrapf(err, "failed to get object %q", *obj.Key)
-			}
-			var data tracepb.TracesData
-			if err := otlp.UnmarshalJSON(body, &data); err != nil {
-				var flattenSpans []*oteleportpb.FlattenSpan
-				dec := otlp.NewJSONDecoder(bytes.NewReader(body))
-				for dec.More() {
-					var span oteleportpb.FlattenSpan
-					if decErr := dec.Decode(&span); decErr != nil {
-						slog.DebugContext(ctx, "failed to decode flatten span", "error", decErr.Error())
-						return false, oops.Wrapf(err, "failed to unmarshal json")
-					}
-					flattenSpans = append(flattenSpans, &span)
+		r.getTracesData,
+		func(ctx context.Context, t time.Time, obj storageObject, data *tracepb.TracesData, skipped bool) (bool, error) {
+			if skipped {
+				slog.DebugContext(ctx, "skip object", "key", obj.Key)
+				if cursorObj.Offset == 0 {
+					cursorObj.CurrentTime = t
+					cursorObj.CurrentObjectKey = Pointer(obj.Key)
 				}
-				data.ResourceSpans = oteleportpb.ConvertFromFlattenSpans(flattenSpans)
+				return true, nil
 			}
+			slog.DebugContext(ctx, "fetch object", "key", obj.Key)
 			resourceSpans := otlp.FilterResourceSpans(
 				data.GetResourceSpans(),
 				otlp.SpanInTimeRangeFilter(startTime, endTime),
+				spanFilter(input.GetFilter()),
 			)
-			dataLen := len(resourceSpans)
-			slog.DebugContext(ctx, "restore spans", "spans", dataLen, "key", *obj.Key, "current", num, "limit", limit)
-			if cursorObj.Offset != 0 && cursorObj.Offset < len(resourceSpans) {
-				resourceSpans = resourceSpans[cursorObj.Offset:]
-				slog.DebugContext(ctx, "skip spans", "offset", cursorObj.Offset, "key", *obj.Key, "spans", len(resourceSpans))
+			slog.DebugContext(ctx, "restore spans", "spans", len(resourceSpans), "key", obj.Key, "current", num, "limit", limit)
+			if cursorObj.Offset != 0 {
+				if cursorObj.Offset < len(resourceSpans) {
+					resourceSpans = resourceSpans[cursorObj.Offset:]
+				} else {
+					resourceSpans = nil
+				}
+				slog.DebugContext(ctx, "skip spans", "offset", cursorObj.Offset, "key", obj.Key, "spans", len(resourceSpans))
 			}
+			dataLen := len(resourceSpans)
 			if num+dataLen > int(limit) {
 				dataLen = int(limit) - num
 				resourceSpans = resourceSpans[:dataLen]
 				cursorObj.Offset += dataLen
-				slog.DebugContext(ctx, "limit over in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", *obj.Key, "spans", len(resourceSpans))
+				slog.DebugContext(ctx, "limit over in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", obj.Key, "spans", len(resourceSpans))
 			} else {
 				cursorObj.CurrentTime = t
-				cursorObj.CurrentObjectKey = obj.Key
+				cursorObj.CurrentObjectKey = Pointer(obj.Key)
 				cursorObj.Offset = 0
-				slog.DebugContext(ctx, "all data in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", *obj.Key, "spans", len(resourceSpans))
+				slog.DebugContext(ctx, "all data in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", obj.Key, "spans", len(resourceSpans))
 			}
 			resp.ResourceSpans = otlp.AppendResourceSpans(resp.GetResourceSpans(), resourceSpans...)
 			num += dataLen
@@ -491,14 +825,168 @@ func (r *S3SignalRepository) FetchTracesData(ctx context.Context, input *otelepo
 	return resp, nil
 }
 
-func (r *S3SignalRepository) FetchMetricsData(ctx context.Context, input *oteleportpb.FetchMetricsDataRequest) (*oteleportpb.FetchMetricsDataResponse, error) {
+// objectFileNamePrefixes are the file name prefixes of objects of signals.
+// resource partition names never contain `-`, so the file name prefix never matches resource partitions.
+var objectFileNamePrefixes = map[string]string{
+	"traces":  "spans-",
+	"metrics": "data-points-",
+	"logs":    "records-",
+}
+
+// objectKeyPrefixes returns the object key prefixes of the signal in the partition of t, for all partition styles.
+// when resourcePartition is not empty, the prefixes are the resource partition and objects directly in the time partition,
+// which are written before partitioning by the resource, so that partitions of other resources are never listed.
+func (r *ObjectSignalRepository) objectKeyPrefixes(signal string, resourcePartition string) func(time.Time) []string {
+	return func(t time.Time) []string {
+		prefixes := make([]string, 0, 4)
+		for _, partition := range r.partitioner.formats(t) {
+			prefix := r.objectKeyPrefix(fmt.Sprintf("%s/%s/", signal, partition))
+			if resourcePartition == "" {
+				prefixes = append(prefixes, prefix)
+				continue
+			}
+			prefixes = append(prefixes, prefix+resourcePartition+"/", prefix+objectFileNamePrefixes[signal])
+		}
+		return prefixes
+	}
+}
+
+// objectKeyPrefix joins the object path prefix, keeping the trailing slash of keyPrefix.
+func (r *ObjectSignalRepository) objectKeyPrefix(keyPrefix string) string {
+	if r.objectPathPrefix == "" {
+		return keyPrefix
+	}
+	key := filepath.Join(r.objectPathPrefix, keyPrefix)
+	if strings.HasSuffix(keyPrefix, "/") {
+		key += "/"
+	}
+	return key
+}
+
+func (r *ObjectSignalRepository) getTracesData(ctx context.Context, obj storageObject) (*tracepb.TracesData, error) {
+	body, err := r.getObjectBody(ctx, obj)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to get object %q", obj.Key)
+	}
+	var data tracepb.TracesData
+	switch storageObjectFormat(obj, body) {
+	case StorageFormatParquet:
+		flattenSpans, err := decodeParquetSpans(body)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode parquet %q", obj.Key)
+		}
+		data.ResourceSpans = oteleportpb.ConvertFromFlattenSpans(flattenSpans)
+		return &data, nil
+	case StorageFormatProtobuf:
+		if isFlattenObject(obj) {
+			flattenSpans, err := decodeDelimited(body, func() *oteleportpb.FlattenSpan { return &oteleportpb.FlattenSpan{} })
+			if err != nil {
+				return nil, oops.Wrapf(err, "failed to decode protobuf %q", obj.Key)
+			}
+			data.ResourceSpans = oteleportpb.ConvertFromFlattenSpans(flattenSpans)
+			return &data, nil
+		}
+		msgs, err := decodeDelimited(body, func() *tracepb.TracesData { return &tracepb.TracesData{} })
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode protobuf %q", obj.Key)
+		}
+		for _, msg := range msgs {
+			data.ResourceSpans = append(data.ResourceSpans, msg.GetResourceSpans()...)
+		}
+		return &data, nil
+	}
+	newFunc := func() *oteleportpb.FlattenSpan { return &oteleportpb.FlattenSpan{} }
+	if isFlattenObject(obj) {
+		flattenSpans, err := decodeJSONLines(body, newFunc)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode json %q", obj.Key)
+		}
+		data.ResourceSpans = oteleportpb.ConvertFromFlattenSpans(flattenSpans)
+		return &data, nil
+	}
+	if err := otlp.UnmarshalJSON(body, &data); err != nil {
+		// objects written before the `.flat.json` suffix have flatten spans with the `.json` suffix.
+		flattenSpans, decErr := decodeJSONLines(body, newFunc)
+		if decErr != nil {
+			slog.DebugContext(ctx, "failed to decode flatten span", "error", decErr.Error())
+			return nil, oops.Wrapf(err, "failed to unmarshal json")
+		}
+		data.ResourceSpans = oteleportpb.ConvertFromFlattenSpans(flattenSpans)
+	}
+	return &data, nil
+}
+
+// defaultTraceLookupRange is the time range to search when GetTraceRequest has no time hint.
+const defaultTraceLookupRange = 24 * time.Hour
+
+func (r *ObjectSignalRepository) GetTrace(ctx context.Context, input *oteleportpb.GetTraceRequest) (*oteleportpb.GetTraceResponse, error) {
+	// time range of index entries is not checked, the time range is only a hint to search partitions.
+	lookup, err := r.newTraceIndexLookup([]string{input.GetTraceId()}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid trace id")
+	}
+	filter := spanFilter(&oteleportpb.TracesFilter{
+		TraceIds: []string{input.GetTraceId()},
+	})
+	endTime := time.Now()
+	if input.GetEndTimeUnixNano() != 0 {
+		endTime = time.Unix(0, int64(input.GetEndTimeUnixNano()))
+	}
+	endTime = endTime.In(time.Local)
+	startTime := endTime.Add(-defaultTraceLookupRange)
+	if input.GetStartTimeUnixNano() != 0 {
+		startTime = time.Unix(0, int64(input.GetStartTimeUnixNano())).In(time.Local)
+	}
+	if startTime.After(endTime) {
+		return nil, status.Error(codes.InvalidArgument, "start time is after end time")
+	}
+	slog.InfoContext(ctx, "get trace", "trace_id", input.GetTraceId(), "start_time", startTime, "end_time", endTime)
+	resp := &oteleportpb.GetTraceResponse{}
+	_, err = walkObjects(
+		ctx,
+		r,
+		startTime,
+		endTime,
+		nil,
+		"traces",
+		"",
+		func(ctx context.Context, t time.Time, obj storageObject) (bool, error) {
+			ok, err := lookup.mayContain(ctx, t, obj.Key)
+			return !ok, err
+		},
+		r.getTracesData,
+		func(ctx context.Context, t time.Time, obj storageObject, data *tracepb.TracesData, skipped bool) (bool, error) {
+			if skipped {
+				slog.DebugContext(ctx, "skip object by trace index", "key", obj.Key)
+				return true, nil
+			}
+			slog.DebugContext(ctx, "fetch object", "key", obj.Key)
+			resourceSpans := otlp.FilterResourceSpans(data.GetResourceSpans(), filter)
+			resp.ResourceSpans = otlp.AppendResourceSpans(resp.GetResourceSpans(), resourceSpans...)
+			return true, nil
+		},
+	)
+	if err != nil {
+		errID := RandomString(8)
+		slog.ErrorContext(ctx, "failed to get trace", "error_id", errID, "error", err.Error())
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get trace: err_id=%s", errID))
+	}
+	if len(resp.GetResourceSpans()) == 0 {
+		return nil, status.Error(codes.NotFound, "trace not found")
+	}
+	slog.InfoContext(ctx, "got trace", "trace_id", input.GetTraceId(), "spans", otlp.TotalSpans(resp.GetResourceSpans()))
+	return resp, nil
+}
+
+func (r *ObjectSignalRepository) FetchMetricsData(ctx context.Context, input *oteleportpb.FetchMetricsDataRequest) (*oteleportpb.FetchMetricsDataResponse, error) {
 	startTime, endTime, limit, err := validateRequest(input.GetStartTimeUnixNano(), input.GetEndTimeUnixNano(), input.GetLimit())
 	if err != nil {
 		return nil, err
 	}
 	cursor := input.GetCursor()
 	slog.InfoContext(ctx, "fetch metrics data", "start_time", startTime, "end_time", endTime, "cursor", cursor, "limit", limit)
-	cursorObj := &s3Cursor{}
+	cursorObj := &objectCursor{}
+	walkStartTime := startTime
 	if cursor != "" {
 		if err := cursorObj.decrypt(cursor, r.cursorEncryptionKey); err != nil {
 			errID := RandomString(8)
@@ -506,64 +994,49 @@ func (r *S3SignalRepository) FetchMetricsData(ctx context.Context, input *otelep
 			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid cursor: %s", errID))
 		}
 		if !cursorObj.CurrentTime.IsZero() {
-			startTime = cursorObj.CurrentTime
+			walkStartTime = cursorObj.CurrentTime
 		}
-		slog.DebugContext(ctx, "cursor", "current_time", cursorObj.CurrentTime, "current_object_key", cursorObj.CurrentObjectKey, "offset", cursorObj.Offset, "start_time", startTime)
+		slog.DebugContext(ctx, "cursor", "current_time", cursorObj.CurrentTime, "current_object_key", cursorObj.CurrentObjectKey, "offset", cursorObj.Offset, "start_time", walkStartTime)
 	}
 	resp := &oteleportpb.FetchMetricsDataResponse{}
 	num := 0
-	noMore, err := r.walkObjects(
+	noMore, err := walkObjects(
 		ctx,
-		startTime,
+		r,
+		walkStartTime,
 		endTime,
 		cursorObj.CurrentObjectKey,
-		func(t time.Time) string {
-			key := fmt.Sprintf("metrics/%s/", t.Format(partitionForamt))
-			if r.objectPathPrefix != "" {
-				key = filepath.Join(r.objectPathPrefix, key)
-			}
-			return key
-		},
-		func(ctx context.Context, t time.Time, obj types.Object) (bool, error) {
-			slog.DebugContext(ctx, "fetch object", "key", *obj.Key)
-			body, err := r.getObjectBody(ctx, obj)
-			if err != nil {
-				return false, oops.Wrapf(err, "failed to get object %q", *obj.Key)
-			}
-			var data metricspb.MetricsData
-			if err := otlp.UnmarshalJSON(body, &data); err != nil {
-				var flattenDataPoints []*oteleportpb.FlattenDataPoint
-				dec := otlp.NewJSONDecoder(bytes.NewReader(body))
-				for dec.More() {
-					var dp oteleportpb.FlattenDataPoint
-					if decErr := dec.Decode(&dp); decErr != nil {
-						slog.DebugContext(ctx, "failed to decode flatten data point", "error", decErr.Error())
-
-						return false, oops.Wrapf(err, "failed to unmarshal json")
-					}
-					flattenDataPoints = append(flattenDataPoints, &dp)
-				}
-				data.ResourceMetrics = oteleportpb.ConvertFromFlattenDataPoints(flattenDataPoints)
-			}
+		"metrics",
+		r.partitioner.filterResourcePartition(input.GetFilter().GetResourceAttributes()),
+		nil,
+		r.getMetricsData,
+		func(ctx context.Context, t time.Time, obj storageObject, data *metricspb.MetricsData, _ bool) (bool, error) {
+			slog.DebugContext(ctx, "fetch object", "key", obj.Key)
 			resourceMetrics := otlp.FilterResourceMetrics(
 				data.GetResourceMetrics(),
 				otlp.MetricDataPointInTimeRangeFilter(startTime, endTime),
+				metricFilter(input.GetFilter()),
 			)
-			dataLen := len(resourceMetrics)
-			slog.DebugContext(ctx, "restore metrics", "metrics", dataLen, "key", *obj.Key)
-			if cursorObj.Offset != 0 && cursorObj.Offset < len(resourceMetrics) {
-				resourceMetrics = resourceMetrics[cursorObj.Offset:]
-				slog.DebugContext(ctx, "skip metrics", "offset", cursorObj.Offset, "key", *obj.Key, "metrics", len(resourceMetrics))
+			slog.DebugContext(ctx, "restore metrics", "metrics", len(resourceMetrics), "key", obj.Key, "current", num, "limit", limit)
+			if cursorObj.Offset != 0 {
+				if cursorObj.Offset < len(resourceMetrics) {
+					resourceMetrics = resourceMetrics[cursorObj.Offset:]
+				} else {
+					resourceMetrics = nil
+				}
+				slog.DebugContext(ctx, "skip metrics", "offset", cursorObj.Offset, "key", obj.Key, "metrics", len(resourceMetrics))
 			}
-			if cursorObj.Offset+dataLen > int(limit) {
-				dataLen = int(limit) - cursorObj.Offset
+			dataLen := len(resourceMetrics)
+			if num+dataLen > int(limit) {
+				dataLen = int(limit) - num
 				resourceMetrics = resourceMetrics[:dataLen]
 				cursorObj.Offset += dataLen
-				slog.DebugContext(ctx, "limit over in one object", "current", cursorObj.Offset, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", *obj.Key, "metrics", len(resourceMetrics))
+				slog.DebugContext(ctx, "limit over in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", obj.Key, "metrics", len(resourceMetrics))
 			} else {
 				cursorObj.CurrentTime = t
-				cursorObj.CurrentObjectKey = obj.Key
+				cursorObj.CurrentObjectKey = Pointer(obj.Key)
 				cursorObj.Offset = 0
+				slog.DebugContext(ctx, "all data in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", obj.Key, "metrics", len(resourceMetrics))
 			}
 			resp.ResourceMetrics = otlp.AppendResourceMetrics(resp.GetResourceMetrics(), resourceMetrics...)
 			num += dataLen
@@ -593,14 +1066,68 @@ func (r *S3SignalRepository) FetchMetricsData(ctx context.Context, input *otelep
 	return resp, nil
 }
 
-func (r *S3SignalRepository) FetchLogsData(ctx context.Context, input *oteleportpb.FetchLogsDataRequest) (*oteleportpb.FetchLogsDataResponse, error) {
+func (r *ObjectSignalRepository) getMetricsData(ctx context.Context, obj storageObject) (*metricspb.MetricsData, error) {
+	body, err := r.getObjectBody(ctx, obj)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to get object %q", obj.Key)
+	}
+	var data metricspb.MetricsData
+	switch storageObjectFormat(obj, body) {
+	case StorageFormatParquet:
+		flattenDataPoints, err := decodeParquetDataPoints(body)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode parquet %q", obj.Key)
+		}
+		data.ResourceMetrics = oteleportpb.ConvertFromFlattenDataPoints(flattenDataPoints)
+		return &data, nil
+	case StorageFormatProtobuf:
+		if isFlattenObject(obj) {
+			flattenDataPoints, err := decodeDelimited(body, func() *oteleportpb.FlattenDataPoint { return &oteleportpb.FlattenDataPoint{} })
+			if err != nil {
+				return nil, oops.Wrapf(err, "failed to decode protobuf %q", obj.Key)
+			}
+			data.ResourceMetrics = oteleportpb.ConvertFromFlattenDataPoints(flattenDataPoints)
+			return &data, nil
+		}
+		msgs, err := decodeDelimited(body, func() *metricspb.MetricsData { return &metricspb.MetricsData{} })
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode protobuf %q", obj.Key)
+		}
+		for _, msg := range msgs {
+			data.ResourceMetrics = append(data.ResourceMetrics, msg.GetResourceMetrics()...)
+		}
+		return &data, nil
+	}
+	newFunc := func() *oteleportpb.FlattenDataPoint { return &oteleportpb.FlattenDataPoint{} }
+	if isFlattenObject(obj) {
+		flattenDataPoints, err := decodeJSONLines(body, newFunc)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode json %q", obj.Key)
+		}
+		data.ResourceMetrics = oteleportpb.ConvertFromFlattenDataPoints(flattenDataPoints)
+		return &data, nil
+	}
+	if err := otlp.UnmarshalJSON(body, &data); err != nil {
+		// objects written before the `.flat.json` suffix have flatten data points with the `.json` suffix.
+		flattenDataPoints, decErr := decodeJSONLines(body, newFunc)
+		if decErr != nil {
+			slog.DebugContext(ctx, "failed to decode flatten data point", "error", decErr.Error())
+			return nil, oops.Wrapf(err, "failed to unmarshal json")
+		}
+		data.ResourceMetrics = oteleportpb.ConvertFromFlattenDataPoints(flattenDataPoints)
+	}
+	return &data, nil
+}
+
+func (r *ObjectSignalRepository) FetchLogsData(ctx context.Context, input *oteleportpb.FetchLogsDataRequest) (*oteleportpb.FetchLogsDataResponse, error) {
 	startTime, endTime, limit, err := validateRequest(input.GetStartTimeUnixNano(), input.GetEndTimeUnixNano(), input.GetLimit())
 	if err != nil {
 		return nil, err
 	}
 	cursor := input.GetCursor()
 	slog.InfoContext(ctx, "fetch logs data", "start_time", startTime, "end_time", endTime, "cursor", cursor, "limit", limit)
-	cursorObj := &s3Cursor{}
+	cursorObj := &objectCursor{}
+	walkStartTime := startTime
 	if cursor != "" {
 		if err := cursorObj.decrypt(cursor, r.cursorEncryptionKey); err != nil {
 			errID := RandomString(8)
@@ -608,62 +1135,49 @@ func (r *S3SignalRepository) FetchLogsData(ctx context.Context, input *oteleport
 			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid cursor: %s", errID))
 		}
 		if !cursorObj.CurrentTime.IsZero() {
-			startTime = cursorObj.CurrentTime
+			walkStartTime = cursorObj.CurrentTime
 		}
-		slog.DebugContext(ctx, "cursor", "current_time", cursorObj.CurrentTime, "current_object_key", cursorObj.CurrentObjectKey, "offset", cursorObj.Offset, "start_time", startTime)
+		slog.DebugContext(ctx, "cursor", "current_time", cursorObj.CurrentTime, "current_object_key", cursorObj.CurrentObjectKey, "offset", cursorObj.Offset, "start_time", walkStartTime)
 	}
 	resp := &oteleportpb.FetchLogsDataResponse{}
 	num := 0
-	noMore, err := r.walkObjects(
+	noMore, err := walkObjects(
 		ctx,
-		startTime,
+		r,
+		walkStartTime,
 		endTime,
 		cursorObj.CurrentObjectKey,
-		func(t time.Time) string {
-			key := fmt.Sprintf("logs/%s/", t.Format(partitionForamt))
-			if r.objectPathPrefix != "" {
-				key = filepath.Join(r.objectPathPrefix, key)
-			}
-			return key
-		},
-		func(ctx context.Context, t time.Time, obj types.Object) (bool, error) {
-			slog.DebugContext(ctx, "fetch object", "key", *obj.Key)
-			body, err := r.getObjectBody(ctx, obj)
-			if err != nil {
-				return false, oops.Wrapf(err, "failed to get object %q", *obj.Key)
-			}
-			var data logspb.LogsData
-			if err := otlp.UnmarshalJSON(body, &data); err != nil {
-				var flattenLogRecords []*oteleportpb.FlattenLogRecord
-				dec := otlp.NewJSONDecoder(bytes.NewReader(body))
-				for dec.More() {
-					var lr oteleportpb.FlattenLogRecord
-					if decErr := dec.Decode(&lr); decErr != nil {
-						slog.DebugContext(ctx, "failed to decode flatten log record", "error", decErr.Error())
-						return false, oops.Wrapf(err, "failed to unmarshal json")
-					}
-					flattenLogRecords = append(flattenLogRecords, &lr)
-				}
-				data.ResourceLogs = oteleportpb.ConvertFromFlattenLogRecords(flattenLogRecords)
-			}
+		"logs",
+		r.partitioner.filterResourcePartition(input.GetFilter().GetResourceAttributes()),
+		nil,
+		r.getLogsData,
+		func(ctx context.Context, t time.Time, obj storageObject, data *logspb.LogsData, _ bool) (bool, error) {
+			slog.DebugContext(ctx, "fetch object", "key", obj.Key)
 			resourceLogs := otlp.FilterResourceLogs(
 				data.GetResourceLogs(),
 				otlp.LogRecordInTimeRangeFilter(startTime, endTime),
+				logRecordFilter(input.GetFilter()),
 			)
-			dataLen := len(resourceLogs)
-			slog.DebugContext(ctx, "restore logs", "logs", dataLen, "key", *obj.Key)
-			if cursorObj.Offset != 0 && cursorObj.Offset < len(resourceLogs) {
-				resourceLogs = resourceLogs[cursorObj.Offset:]
-				slog.DebugContext(ctx, "skip logs", "offset", cursorObj.Offset, "key", *obj.Key, "logs", len(resourceLogs))
+			slog.DebugContext(ctx, "restore logs", "logs", len(resourceLogs), "key", obj.Key, "current", num, "limit", limit)
+			if cursorObj.Offset != 0 {
+				if cursorObj.Offset < len(resourceLogs) {
+					resourceLogs = resourceLogs[cursorObj.Offset:]
+				} else {
+					resourceLogs = nil
+				}
+				slog.DebugContext(ctx, "skip logs", "offset", cursorObj.Offset, "key", obj.Key, "logs", len(resourceLogs))
 			}
-			if cursorObj.Offset+dataLen > int(limit) {
-				dataLen = int(limit) - cursorObj.Offset
+			dataLen := len(resourceLogs)
+			if num+dataLen > int(limit) {
+				dataLen = int(limit) - num
 				resourceLogs = resourceLogs[:dataLen]
 				cursorObj.Offset += dataLen
+				slog.DebugContext(ctx, "limit over in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", obj.Key, "logs", len(resourceLogs))
 			} else {
 				cursorObj.CurrentTime = t
-				cursorObj.CurrentObjectKey = obj.Key
+				cursorObj.CurrentObjectKey = Pointer(obj.Key)
 				cursorObj.Offset = 0
+				slog.DebugContext(ctx, "all data in one object", "current", num, "limit", limit, "data_len", dataLen, "offset", cursorObj.Offset, "key", obj.Key, "logs", len(resourceLogs))
 			}
 			resp.ResourceLogs = otlp.AppendResourceLogs(resp.GetResourceLogs(), resourceLogs...)
 			num += dataLen
@@ -693,6 +1207,59 @@ func (r *S3SignalRepository) FetchLogsData(ctx context.Context, input *oteleport
 	return resp, nil
 }
 
+func (r *ObjectSignalRepository) getLogsData(ctx context.Context, obj storageObject) (*logspb.LogsData, error) {
+	body, err := r.getObjectBody(ctx, obj)
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to get object %q", obj.Key)
+	}
+	var data logspb.LogsData
+	switch storageObjectFormat(obj, body) {
+	case StorageFormatParquet:
+		flattenLogRecords, err := decodeParquetLogRecords(body)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode parquet %q", obj.Key)
+		}
+		data.ResourceLogs = oteleportpb.ConvertFromFlattenLogRecords(flattenLogRecords)
+		return &data, nil
+	case StorageFormatProtobuf:
+		if isFlattenObject(obj) {
+			flattenLogRecords, err := decodeDelimited(body, func() *oteleportpb.FlattenLogRecord { return &oteleportpb.FlattenLogRecord{} })
+			if err != nil {
+				return nil, oops.Wrapf(err, "failed to decode protobuf %q", obj.Key)
+			}
+			data.ResourceLogs = oteleportpb.ConvertFromFlattenLogRecords(flattenLogRecords)
+			return &data, nil
+		}
+		msgs, err := decodeDelimited(body, func() *logspb.LogsData { return &logspb.LogsData{} })
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode protobuf %q", obj.Key)
+		}
+		for _, msg := range msgs {
+			data.ResourceLogs = append(data.ResourceLogs, msg.GetResourceLogs()...)
+		}
+		return &data, nil
+	}
+	newFunc := func() *oteleportpb.FlattenLogRecord { return &oteleportpb.FlattenLogRecord{} }
+	if isFlattenObject(obj) {
+		flattenLogRecords, err := decodeJSONLines(body, newFunc)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to decode json %q", obj.Key)
+		}
+		data.ResourceLogs = oteleportpb.ConvertFromFlattenLogRecords(flattenLogRecords)
+		return &data, nil
+	}
+	if err := otlp.UnmarshalJSON(body, &data); err != nil {
+		// objects written before the `.flat.json` suffix have flatten log records with the `.json` suffix.
+		flattenLogRecords, decErr := decodeJSONLines(body, newFunc)
+		if decErr != nil {
+			slog.DebugContext(ctx, "failed to decode flatten log record", "error", decErr.Error())
+			return nil, oops.Wrapf(err, "failed to unmarshal json")
+		}
+		data.ResourceLogs = oteleportpb.ConvertFromFlattenLogRecords(flattenLogRecords)
+	}
+	return &data, nil
+}
+
 func validateRequest(startTimeUnixNano uint64, endTimeUnixNano uint64, limit int64) (time.Time, time.Time, int64, error) {
 	if startTimeUnixNano == 0 {
 		return time.Time{}, time.Time{}, 0, status.Error(codes.InvalidArgument, "start time is required")
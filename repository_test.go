@@ -0,0 +1,1068 @@
+package oteleport_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mashiike/go-otlp-helper/otlp"
+	"github.com/mashiike/oteleport"
+	oteleportpb "github.com/mashiike/oteleport/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testBaseTime = time.Date(2024, 11, 5, 13, 30, 0, 0, time.UTC)
+
+func newTestMemoryRepository(t *testing.T, memCfg oteleport.StorageMemoryConfig) oteleport.SignalRepository {
+	t.Helper()
+	return newTestRepository(t, oteleport.StorageConfig{
+		Location: "memory://",
+		Memory:   memCfg,
+	})
+}
+
+func newTestRepository(t *testing.T, storageCfg oteleport.StorageConfig) oteleport.SignalRepository {
+	t.Helper()
+	cfg := oteleport.DefaultServerConfig()
+	cfg.Storage = storageCfg
+	cfg.Storage.CursorEncryptionKey = []byte("r0JwTGIzoOpTi+gH9t+6i/kIwxDi7kR23uwKAeSxxEE=")
+	require.NoError(t, cfg.Storage.Validate(cfg))
+	repo, err := oteleport.NewSignalRepository(&cfg.Storage)
+	require.NoError(t, err)
+	return repo
+}
+
+func newTestTracesData(batch int, n int) *tracepb.TracesData {
+	spans := make([]*tracepb.Span, 0, n)
+	for i := 0; i < n; i++ {
+		start := testBaseTime.Add(time.Duration(batch*n+i) * time.Second)
+		spans = append(spans, &tracepb.Span{
+			TraceId:           []byte(fmt.Sprintf("trace-%010d", batch)),
+			SpanId:            []byte(fmt.Sprintf("span%04d", batch*n+i)),
+			Name:              fmt.Sprintf("span-%d-%d", batch, i),
+			StartTimeUnixNano: uint64(start.UnixNano()),
+			EndTimeUnixNano:   uint64(start.Add(100 * time.Millisecond).UnixNano()),
+		})
+	}
+	return &tracepb.TracesData{
+		ResourceSpans: []*tracepb.ResourceSpans{
+			{
+				Resource: &resourcepb.Resource{
+					Attributes: []*commonpb.KeyValue{
+						{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "test"}}},
+					},
+				},
+				ScopeSpans: []*tracepb.ScopeSpans{
+					{Spans: spans},
+				},
+			},
+		},
+	}
+}
+
+func fetchAllSpanNames(t *testing.T, repo oteleport.SignalRepository, req *oteleportpb.FetchTracesDataRequest) []string {
+	t.Helper()
+	ctx := context.Background()
+	names := make([]string, 0)
+	for page := 0; page < 100; page++ {
+		resp, err := repo.FetchTracesData(ctx, req)
+		require.NoError(t, err)
+		for _, rs := range resp.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				for _, span := range ss.GetSpans() {
+					names = append(names, span.GetName())
+				}
+			}
+		}
+		if !resp.GetHasMore() {
+			return names
+		}
+		req.Cursor = resp.GetNextCursor()
+	}
+	t.Fatal("too many pages")
+	return nil
+}
+
+func TestMemoryRepository__Pagination(t *testing.T) {
+	repo := newTestMemoryRepository(t, oteleport.StorageMemoryConfig{})
+	ctx := context.Background()
+	expected := make([]string, 0)
+	for batch := 0; batch < 3; batch++ {
+		data := newTestTracesData(batch, 5)
+		require.NoError(t, repo.PushTracesData(ctx, data))
+		for _, span := range data.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans() {
+			expected = append(expected, span.GetName())
+		}
+	}
+	actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             4,
+	})
+	require.ElementsMatch(t, expected, actual)
+}
+
+func TestMemoryRepository__Eviction(t *testing.T) {
+	repo := newTestMemoryRepository(t, oteleport.StorageMemoryConfig{
+		MaxSignals: 5,
+	})
+	ctx := context.Background()
+	var last *tracepb.TracesData
+	for batch := 0; batch < 3; batch++ {
+		last = newTestTracesData(batch, 5)
+		require.NoError(t, repo.PushTracesData(ctx, last))
+	}
+	expected := make([]string, 0)
+	for _, span := range last.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans() {
+		expected = append(expected, span.GetName())
+	}
+	actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.ElementsMatch(t, expected, actual)
+}
+
+func TestMemoryRepository__Filter(t *testing.T) {
+	repo := newTestMemoryRepository(t, oteleport.StorageMemoryConfig{})
+	ctx := context.Background()
+	expected := make([]string, 0)
+	for batch := 0; batch < 3; batch++ {
+		data := newTestTracesData(batch, 5)
+		if batch == 1 {
+			data.GetResourceSpans()[0].GetResource().GetAttributes()[0].Value = &commonpb.AnyValue{
+				Value: &commonpb.AnyValue_StringValue{StringValue: "other"},
+			}
+			for _, span := range data.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans() {
+				expected = append(expected, span.GetName())
+			}
+		}
+		require.NoError(t, repo.PushTracesData(ctx, data))
+	}
+	actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             2,
+		Filter: &oteleportpb.TracesFilter{
+			ResourceAttributes: []*oteleportpb.AttributeMatcher{
+				{Key: "service.name", Value: "other"},
+			},
+		},
+	})
+	require.ElementsMatch(t, expected, actual)
+
+	actual = fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             2,
+		Filter: &oteleportpb.TracesFilter{
+			SpanNames: []string{"span-0-1", "span-2-3"},
+		},
+	})
+	require.ElementsMatch(t, []string{"span-0-1", "span-2-3"}, actual)
+}
+
+func TestMemoryRepository__GetTrace(t *testing.T) {
+	repo := newTestMemoryRepository(t, oteleport.StorageMemoryConfig{})
+	ctx := context.Background()
+	for batch := 0; batch < 3; batch++ {
+		require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+	}
+	resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 1))),
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.NoError(t, err)
+	names := make([]string, 0)
+	for _, rs := range resp.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				names = append(names, span.GetName())
+			}
+		}
+	}
+	require.ElementsMatch(t, []string{"span-1-0", "span-1-1", "span-1-2", "span-1-3", "span-1-4"}, names)
+
+	_, err = repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 9))),
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId: "not-hex",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestFileRepository__TraceIndex(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+	})
+	ctx := context.Background()
+	for batch := 0; batch < 3; batch++ {
+		require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+	}
+	indexFiles, err := filepath.Glob(filepath.Join(dir, "traces-index", "*", "*", "*", "*", "index-*.json.gz"))
+	require.NoError(t, err)
+	require.Len(t, indexFiles, 3)
+
+	// break span objects, objects without the trace must not be read.
+	spanFiles, err := filepath.Glob(filepath.Join(dir, "traces", "*", "*", "*", "*", "spans-*.json.gz"))
+	require.NoError(t, err)
+	require.Len(t, spanFiles, 3)
+	traceID := hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 1)))
+	for _, spanFile := range spanFiles {
+		bs, err := os.ReadFile(spanFile)
+		require.NoError(t, err)
+		gr, err := gzip.NewReader(bytes.NewReader(bs))
+		require.NoError(t, err)
+		body, err := io.ReadAll(gr)
+		require.NoError(t, err)
+		if !strings.Contains(string(body), strings.ToUpper(traceID)) {
+			require.NoError(t, os.WriteFile(spanFile, []byte("broken"), 0644))
+		}
+	}
+
+	resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           traceID,
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans(), 5)
+
+	actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             2,
+		Filter: &oteleportpb.TracesFilter{
+			TraceIds: []string{traceID},
+		},
+	})
+	require.ElementsMatch(t, []string{"span-1-0", "span-1-1", "span-1-2", "span-1-3", "span-1-4"}, actual)
+}
+
+func TestFileRepository__Parquet(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Format:   oteleport.StorageFormatParquet,
+	})
+	ctx := context.Background()
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	spanFiles, err := filepath.Glob(filepath.Join(dir, "traces", "*", "*", "*", "*", "spans-*.parquet"))
+	require.NoError(t, err)
+	require.Len(t, spanFiles, 1)
+	bs, err := os.ReadFile(spanFiles[0])
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(bs, []byte("PAR1")), "span object must be parquet")
+
+	resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 0))),
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, otlp.TotalSpans(resp.GetResourceSpans()))
+}
+
+func TestFileRepository__Protobuf(t *testing.T) {
+	cases := []struct {
+		name    string
+		flatten bool
+		pattern string
+	}{
+		{name: "otlp", flatten: false, pattern: "spans-*.binpb.zst"},
+		{name: "flatten", flatten: true, pattern: "spans-*.flat.binpb.zst"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			repo := newTestRepository(t, oteleport.StorageConfig{
+				Location:    "file://" + dir,
+				Format:      oteleport.StorageFormatProtobuf,
+				Flatten:     oteleport.Pointer(c.flatten),
+				Compression: oteleport.CompressionZstd,
+			})
+			ctx := context.Background()
+			for batch := 0; batch < 3; batch++ {
+				require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+			}
+			spanFiles, err := filepath.Glob(filepath.Join(dir, "traces", "*", "*", "*", "*", c.pattern))
+			require.NoError(t, err)
+			require.Len(t, spanFiles, 3)
+			names := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+				StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+				EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+				Limit:             4,
+			})
+			require.Len(t, names, 15)
+		})
+	}
+}
+
+func TestFileRepository__FlattenJSON(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Flatten:  oteleport.Pointer(true),
+	})
+	ctx := context.Background()
+	for batch := 0; batch < 3; batch++ {
+		require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+	}
+	spanFiles, err := filepath.Glob(filepath.Join(dir, "traces", "*", "*", "*", "*", "spans-*.flat.json.gz"))
+	require.NoError(t, err)
+	require.Len(t, spanFiles, 3)
+	// objects written before the `.flat.json` suffix are still read.
+	legacy := strings.Replace(spanFiles[0], ".flat.json.gz", ".json.gz", 1)
+	require.NoError(t, os.Rename(spanFiles[0], legacy))
+	names := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.Len(t, names, 15)
+}
+
+func TestFileRepository__Partition(t *testing.T) {
+	cases := []struct {
+		name      string
+		partition oteleport.StoragePartitionConfig
+		style     string
+		expected  []string
+	}{
+		{
+			name:      "default",
+			partition: oteleport.StoragePartitionConfig{},
+			expected:  []string{"2024/11/05/13"},
+		},
+		{
+			name:      "minute",
+			partition: oteleport.StoragePartitionConfig{Granularity: "minute"},
+			expected:  []string{"2024/11/05/13/30", "2024/11/05/13/31", "2024/11/05/13/32"},
+		},
+		{
+			name:      "hour_in_tokyo",
+			partition: oteleport.StoragePartitionConfig{Timezone: "Asia/Tokyo"},
+			expected:  []string{"2024/11/05/22"},
+		},
+		{
+			name:      "day_in_los_angeles",
+			partition: oteleport.StoragePartitionConfig{Granularity: "day", Timezone: "America/Los_Angeles"},
+			expected:  []string{"2024/11/05"},
+		},
+		{
+			name:     "hive",
+			style:    "hive",
+			expected: []string{"year=2024/month=11/day=05/hour=13"},
+		},
+		{
+			name:      "hive_with_service",
+			partition: oteleport.StoragePartitionConfig{ResourceAttribute: "service.name"},
+			style:     "hive",
+			expected:  []string{"year=2024/month=11/day=05/hour=13/service=test"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			repo := newTestRepository(t, oteleport.StorageConfig{
+				Location:       "file://" + dir,
+				Partition:      c.partition,
+				PartitionStyle: c.style,
+			})
+			ctx := context.Background()
+			expectedNames := make([]string, 0)
+			// 30 batches, 5 spans per batch, 1 second apart
+			for batch := 0; batch < 30; batch++ {
+				require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+				for i := 0; i < 5; i++ {
+					expectedNames = append(expectedNames, fmt.Sprintf("span-%d-%d", batch, i))
+				}
+			}
+			partitions := make([]string, 0)
+			err := filepath.WalkDir(filepath.Join(dir, "traces"), func(p string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(filepath.Join(dir, "traces"), filepath.Dir(p))
+				if err != nil {
+					return err
+				}
+				partitions = append(partitions, filepath.ToSlash(rel))
+				return nil
+			})
+			require.NoError(t, err)
+			partitions = lo.Uniq(partitions)
+			require.ElementsMatch(t, c.expected, partitions)
+
+			actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+				StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+				EndTimeUnixNano:   uint64(testBaseTime.Add(5 * time.Minute).UnixNano()),
+				Limit:             7,
+			})
+			require.ElementsMatch(t, expectedNames, actual)
+		})
+	}
+}
+
+func TestFileRepository__PartitionLocalTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// older versions wrote partitions in the server local timezone
+	local := time.Local
+	time.Local = tokyo
+	t.Cleanup(func() { time.Local = local })
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	legacyRepo := newTestRepository(t, oteleport.StorageConfig{
+		Location:  "file://" + dir,
+		Partition: oteleport.StoragePartitionConfig{Timezone: "Local"},
+	})
+	require.NoError(t, legacyRepo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	_, err = os.Stat(filepath.Join(dir, "traces", "2024", "11", "05", "22"))
+	require.NoError(t, err, "written in the partition of the local timezone")
+
+	newFetchReq := func() *oteleportpb.FetchTracesDataRequest {
+		return &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+			EndTimeUnixNano:   uint64(testBaseTime.Add(time.Minute).UnixNano()),
+		}
+	}
+	utcRepo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+	})
+	require.Empty(t, fetchAllSpanNames(t, utcRepo, newFetchReq()), "partitions of the local timezone are not listed in UTC")
+
+	localRepo := newTestRepository(t, oteleport.StorageConfig{
+		Location:  "file://" + dir,
+		Partition: oteleport.StoragePartitionConfig{Timezone: "Local"},
+	})
+	require.ElementsMatch(t, []string{
+		"span-0-0", "span-0-1", "span-0-2", "span-0-3", "span-0-4",
+	}, fetchAllSpanNames(t, localRepo, newFetchReq()))
+}
+
+func TestFileRepository__MixedPartitionStyle(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	expectedNames := make([]string, 0)
+	for i, style := range []string{"default", "hive", "default", "hive"} {
+		repo := newTestRepository(t, oteleport.StorageConfig{
+			Location:       "file://" + dir,
+			PartitionStyle: style,
+		})
+		for batch := i * 10; batch < (i+1)*10; batch++ {
+			require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+			for j := 0; j < 5; j++ {
+				expectedNames = append(expectedNames, fmt.Sprintf("span-%d-%d", batch, j))
+			}
+		}
+	}
+	for _, style := range []string{"default", "hive"} {
+		t.Run(style, func(t *testing.T) {
+			repo := newTestRepository(t, oteleport.StorageConfig{
+				Location:       "file://" + dir,
+				PartitionStyle: style,
+			})
+			actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+				StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+				EndTimeUnixNano:   uint64(testBaseTime.Add(5 * time.Minute).UnixNano()),
+				Limit:             7,
+			})
+			require.ElementsMatch(t, expectedNames, actual)
+
+			traceID := hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 15)))
+			resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+				TraceId:           traceID,
+				StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+				EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+			})
+			require.NoError(t, err)
+			require.Equal(t, 5, otlp.TotalSpans(resp.GetResourceSpans()))
+		})
+	}
+}
+
+func TestFileRepository__ResourcePartition(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	// objects written before partitioning by the resource
+	legacyRepo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+	})
+	require.NoError(t, legacyRepo.PushTracesData(ctx, newTestTracesData(0, 5)))
+
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Partition: oteleport.StoragePartitionConfig{
+			ResourceAttribute: "service.name",
+		},
+	})
+	for batch := 1; batch < 4; batch++ {
+		data := newTestTracesData(batch, 5)
+		if batch == 2 {
+			data.GetResourceSpans()[0].GetResource().GetAttributes()[0].Value = &commonpb.AnyValue{
+				Value: &commonpb.AnyValue_StringValue{StringValue: "other service"},
+			}
+		}
+		require.NoError(t, repo.PushTracesData(ctx, data))
+	}
+	otherFiles, err := filepath.Glob(filepath.Join(dir, "traces", "2024", "11", "05", "13", "service=other%20service", "spans-*.json.gz"))
+	require.NoError(t, err)
+	require.Len(t, otherFiles, 1)
+	testFiles, err := filepath.Glob(filepath.Join(dir, "traces", "2024", "11", "05", "13", "service=test", "spans-*.json.gz"))
+	require.NoError(t, err)
+	require.Len(t, testFiles, 2)
+
+	// break objects of the test service, they must be pruned.
+	for _, testFile := range testFiles {
+		require.NoError(t, os.WriteFile(testFile, []byte("broken"), 0644))
+	}
+	actual := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             2,
+		Filter: &oteleportpb.TracesFilter{
+			ResourceAttributes: []*oteleportpb.AttributeMatcher{
+				{Key: "service.name", Value: "other service"},
+			},
+		},
+	})
+	require.ElementsMatch(t, []string{"span-2-0", "span-2-1", "span-2-2", "span-2-3", "span-2-4"}, actual)
+
+	// objects of other services are never listed, and objects written before partitioning by the resource are read.
+	cfg := oteleport.DefaultServerConfig()
+	cfg.Storage = oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Partition: oteleport.StoragePartitionConfig{
+			ResourceAttribute: "service.name",
+		},
+		CursorEncryptionKey: []byte("r0JwTGIzoOpTi+gH9t+6i/kIwxDi7kR23uwKAeSxxEE="),
+	}
+	require.NoError(t, cfg.Storage.Validate(cfg))
+	var mu sync.Mutex
+	listed := make([]string, 0)
+	recordingRepo := oteleport.NewListRecordingSignalRepository(&cfg.Storage, func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		listed = append(listed, key)
+	})
+	actual = fetchAllSpanNames(t, recordingRepo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             3,
+		Filter: &oteleportpb.TracesFilter{
+			ResourceAttributes: []*oteleportpb.AttributeMatcher{
+				{Key: "service.name", Value: "other service"},
+			},
+		},
+	})
+	require.ElementsMatch(t, []string{"span-2-0", "span-2-1", "span-2-2", "span-2-3", "span-2-4"}, actual)
+	for _, key := range listed {
+		require.NotContains(t, key, "service=test")
+	}
+	require.Contains(t, lo.Map(listed, func(key string, _ int) string { return path.Dir(key) }), "traces/2024/11/05/13")
+}
+
+func TestFileRepository__Batch(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Batch: oteleport.StorageBatchConfig{
+			Enable:     oteleport.Pointer(true),
+			MaxRecords: 10,
+			MaxAge:     "200ms",
+		},
+	})
+	ctx := context.Background()
+	countSpanFiles := func() int {
+		spanFiles, err := filepath.Glob(filepath.Join(dir, "traces", "*", "*", "*", "*", "spans-*.json.gz"))
+		require.NoError(t, err)
+		return len(spanFiles)
+	}
+	fetchReq := func() *oteleportpb.FetchTracesDataRequest {
+		return &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+			EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		}
+	}
+	for batch := 0; batch < 3; batch++ {
+		require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+	}
+	require.Equal(t, 1, countSpanFiles(), "flushed by max records")
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq()), 10)
+
+	require.NoError(t, repo.Flush(ctx))
+	require.Equal(t, 2, countSpanFiles(), "flushed by flush")
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq()), 15)
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go repo.RunFlushLoop(loopCtx)
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(3, 5)))
+	require.Equal(t, 2, countSpanFiles())
+	require.Eventually(t, func() bool {
+		return countSpanFiles() == 3
+	}, 2*time.Second, 50*time.Millisecond, "flushed by max age")
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq()), 20)
+
+	traceID := hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 3)))
+	resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           traceID,
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, otlp.TotalSpans(resp.GetResourceSpans()))
+}
+
+func TestFileRepository__Compact(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Partition: oteleport.StoragePartitionConfig{
+			ResourceAttribute: "service.name",
+		},
+	})
+	ctx := context.Background()
+	for batch := 0; batch < 10; batch++ {
+		require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+	}
+	globFiles := func(pattern string) []string {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		require.NoError(t, err)
+		return files
+	}
+	require.Len(t, globFiles("traces/*/*/*/*/service=test/spans-*.json.gz"), 10)
+	require.Len(t, globFiles("traces-index/*/*/*/*/service=test/index-*.json.gz"), 10)
+
+	result, err := repo.Compact(ctx, &oteleport.CompactOptions{
+		Signal:    "traces",
+		StartTime: testBaseTime.Add(-time.Hour),
+		EndTime:   testBaseTime.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 10, result.SourceObjects)
+	require.Equal(t, 1, result.CompactedObjects)
+	require.Len(t, globFiles("traces/*/*/*/*/service=test/spans-*.json.gz"), 1)
+	require.Len(t, globFiles("traces-index/*/*/*/*/service=test/index-*.json.gz"), 1)
+
+	names := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             7,
+	})
+	require.Len(t, names, 50)
+	traceID := hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", 3)))
+	resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           traceID,
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, otlp.TotalSpans(resp.GetResourceSpans()))
+
+	// compacting again does nothing, a partition with a single object is left as is.
+	result, err = repo.Compact(ctx, &oteleport.CompactOptions{
+		Signal:    "traces",
+		StartTime: testBaseTime.Add(-time.Hour),
+		EndTime:   testBaseTime.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, result.CompactedObjects)
+}
+
+func TestFileRepository__Purge(t *testing.T) {
+	dir := t.TempDir()
+	storageCfg := oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Retention: oteleport.StorageRetentionConfig{
+			Traces: "1h",
+		},
+	}
+	repo := newTestRepository(t, storageCfg)
+	ctx := context.Background()
+	// 720 batches of 5 spans are 1 hour.
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1440, 5)))
+	storageCfg.PartitionStyle = oteleport.PartitionStyleHive
+	hiveRepo := newTestRepository(t, storageCfg)
+	require.NoError(t, hiveRepo.PushTracesData(ctx, newTestTracesData(360, 5)))
+
+	purgeOpts := &oteleport.PurgeOptions{
+		DryRun: true,
+		Now:    time.Date(2024, 11, 5, 16, 10, 0, 0, time.UTC),
+	}
+	results, err := repo.Purge(ctx, purgeOpts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "traces", results[0].Signal)
+	require.Equal(t, 4, results[0].Objects, "spans and index objects of 13:00 and 14:00")
+	require.Greater(t, results[0].Bytes, int64(0))
+	fetchReq := func() *oteleportpb.FetchTracesDataRequest {
+		return &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: uint64(testBaseTime.Add(-time.Hour).UnixNano()),
+			EndTimeUnixNano:   uint64(testBaseTime.Add(3 * time.Hour).UnixNano()),
+		}
+	}
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq()), 15, "dry run deletes nothing")
+
+	purgeOpts.DryRun = false
+	results, err = repo.Purge(ctx, purgeOpts)
+	require.NoError(t, err)
+	require.Equal(t, 4, results[0].Objects)
+	require.ElementsMatch(t, []string{
+		"span-1440-0", "span-1440-1", "span-1440-2", "span-1440-3", "span-1440-4",
+	}, fetchAllSpanNames(t, repo, fetchReq()))
+	_, err = os.Stat(filepath.Join(dir, "traces", "2024", "11", "05", "13"))
+	require.ErrorIs(t, err, fs.ErrNotExist, "emptied partition directory is removed")
+
+	results, err = repo.Purge(ctx, purgeOpts)
+	require.NoError(t, err)
+	require.Equal(t, 0, results[0].Objects)
+}
+
+func TestFileRepository__PurgeMixedGranularity(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	storageCfg := func(granularity string) oteleport.StorageConfig {
+		return oteleport.StorageConfig{
+			Location: "file://" + dir,
+			Partition: oteleport.StoragePartitionConfig{
+				Granularity: granularity,
+			},
+			Retention: oteleport.StorageRetentionConfig{
+				Traces: "1h",
+			},
+		}
+	}
+	// 17280 batches of 5 spans are 1 day.
+	dayRepo := newTestRepository(t, storageCfg(oteleport.PartitionGranularityDay))
+	require.NoError(t, dayRepo.PushTracesData(ctx, newTestTracesData(-17280, 5)))
+	minuteRepo := newTestRepository(t, storageCfg(oteleport.PartitionGranularityMinute))
+	require.NoError(t, minuteRepo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	repo := newTestRepository(t, storageCfg(oteleport.PartitionGranularityHour))
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1440, 5)))
+
+	results, err := repo.Purge(ctx, &oteleport.PurgeOptions{
+		Now: time.Date(2024, 11, 5, 16, 10, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, results[0].Objects, "spans and index objects of the day 2024-11-04 and the minute 13:30")
+	require.ElementsMatch(t, []string{
+		"span-1440-0", "span-1440-1", "span-1440-2", "span-1440-3", "span-1440-4",
+	}, fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-48 * time.Hour).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(3 * time.Hour).UnixNano()),
+	}))
+	_, err = os.Stat(filepath.Join(dir, "traces", "2024", "11", "04"))
+	require.ErrorIs(t, err, fs.ErrNotExist, "the partition of the day granularity is purged")
+}
+
+func TestFileRepository__PurgeTenants(t *testing.T) {
+	dir := t.TempDir()
+	storageCfg := oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Retention: oteleport.StorageRetentionConfig{
+			Traces: "1h",
+		},
+	}
+	ctx := context.Background()
+	// tenants only in bearer tokens are not in the config, and are known by the storage after restart.
+	writer := newTestRepository(t, storageCfg)
+	require.NoError(t, writer.ForTenant("team-a").PushTracesData(ctx, newTestTracesData(0, 5)))
+	require.NoError(t, writer.ForTenant("team-b").PushTracesData(ctx, newTestTracesData(1440, 5)))
+
+	repo := newTestRepository(t, storageCfg)
+	results, err := repo.Purge(ctx, &oteleport.PurgeOptions{
+		Now: time.Date(2024, 11, 5, 16, 10, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	objects := make(map[string]int)
+	for _, result := range results {
+		objects[result.Tenant] += result.Objects
+	}
+	require.Equal(t, map[string]int{"": 0, "team-a": 2, "team-b": 0}, objects)
+	_, err = os.Stat(filepath.Join(dir, "tenants", "team-a", "traces", "2024", "11", "05", "13"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.Len(t, fetchAllSpanNames(t, repo.ForTenant("team-b"), &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Hour).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(3 * time.Hour).UnixNano()),
+	}), 5)
+}
+
+func TestFileRepository__Compression(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	cases := []struct {
+		compression string
+		suffix      string
+	}{
+		{compression: oteleport.CompressionGZip, suffix: ".json.gz"},
+		{compression: oteleport.CompressionZstd, suffix: ".json.zst"},
+		{compression: oteleport.CompressionSnappy, suffix: ".json.sz"},
+		{compression: oteleport.CompressionNone, suffix: ".json"},
+	}
+	var repo oteleport.SignalRepository
+	for batch, c := range cases {
+		// all compressions are written to the same location, and read by any of them.
+		repo = newTestRepository(t, oteleport.StorageConfig{
+			Location:    "file://" + dir,
+			Compression: c.compression,
+		})
+		require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(batch, 5)))
+		spanFiles, err := filepath.Glob(filepath.Join(dir, "traces", "*", "*", "*", "*", "spans-*"+c.suffix))
+		require.NoError(t, err)
+		require.Len(t, spanFiles, 1, c.compression)
+	}
+	names := fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		Limit:             3,
+	})
+	require.Len(t, names, 20)
+	for batch := range cases {
+		traceID := hex.EncodeToString([]byte(fmt.Sprintf("trace-%010d", batch)))
+		resp, err := repo.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+			TraceId:           traceID,
+			StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+			EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, otlp.TotalSpans(resp.GetResourceSpans()))
+	}
+}
+
+func TestFileRepository__Prefetch(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	sequential := newTestRepository(t, oteleport.StorageConfig{
+		Location:        "file://" + dir,
+		PrefetchWorkers: 1,
+	})
+	for batch := 0; batch < 30; batch++ {
+		require.NoError(t, sequential.PushTracesData(ctx, newTestTracesData(batch, 5)))
+	}
+	concurrent := newTestRepository(t, oteleport.StorageConfig{
+		Location:        "file://" + dir,
+		PrefetchWorkers: 8,
+	})
+	fetchReq := func() *oteleportpb.FetchTracesDataRequest {
+		return &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+			EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+			Limit:             7,
+		}
+	}
+	expected := fetchAllSpanNames(t, sequential, fetchReq())
+	require.Len(t, expected, 150)
+	require.Equal(t, expected, fetchAllSpanNames(t, concurrent, fetchReq()), "prefetch keeps the order of the cursor")
+}
+
+func TestFileRepository__Manifest(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Manifest: oteleport.StorageManifestConfig{
+			Enable: oteleport.Pointer(true),
+		},
+	})
+	// 720 batches of 5 spans are 1 hour.
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1, 5)))
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1440, 5)))
+	legacyRepo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+	})
+	require.NoError(t, legacyRepo.PushTracesData(ctx, newTestTracesData(720, 5)))
+
+	markers, err := filepath.Glob(filepath.Join(dir, "manifest", "traces", "*", "*", "*", "*", "partition.json"))
+	require.NoError(t, err)
+	require.Len(t, markers, 2)
+	fetchReq := func() *oteleportpb.FetchTracesDataRequest {
+		return &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: uint64(testBaseTime.Add(-time.Hour).UnixNano()),
+			EndTimeUnixNano:   uint64(testBaseTime.Add(3 * time.Hour).UnixNano()),
+			Limit:             4,
+		}
+	}
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq()), 15, "partition without the marker is skipped")
+	ranges, err := repo.TimeRanges(ctx, "traces")
+	require.NoError(t, err)
+	require.Equal(t, []*oteleport.TimeRange{
+		{StartTime: time.Date(2024, 11, 5, 13, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 11, 5, 14, 0, 0, 0, time.UTC)},
+		{StartTime: time.Date(2024, 11, 5, 15, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 11, 5, 16, 0, 0, 0, time.UTC)},
+	}, ranges)
+
+	n, err := repo.RebuildManifest(ctx, "traces")
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq()), 20)
+	ranges, err = repo.TimeRanges(ctx, "traces")
+	require.NoError(t, err)
+	require.Equal(t, []*oteleport.TimeRange{
+		{StartTime: time.Date(2024, 11, 5, 13, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 11, 5, 16, 0, 0, 0, time.UTC)},
+	}, ranges)
+}
+
+func TestFileRepository__ManifestPreExisting(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	legacyRepo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+	})
+	require.NoError(t, legacyRepo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Manifest: oteleport.StorageManifestConfig{
+			Enable: oteleport.Pointer(true),
+		},
+	})
+	fetchReq := &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Hour).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	}
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq), 5, "objects stored before enabling the manifest are read")
+
+	n, err := repo.RebuildManifest(ctx, "traces")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq), 5)
+}
+
+func TestFileRepository__ManifestPurgedByOtherInstance(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	storageCfg := oteleport.StorageConfig{
+		Location: "file://" + dir,
+		Manifest: oteleport.StorageManifestConfig{
+			Enable: oteleport.Pointer(true),
+		},
+		Retention: oteleport.StorageRetentionConfig{
+			Traces: "1h",
+		},
+	}
+	repo := newTestRepository(t, storageCfg)
+	otherRepo := newTestRepository(t, storageCfg)
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(0, 5)))
+	results, err := otherRepo.Purge(ctx, &oteleport.PurgeOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 3, results[0].Objects, "spans, index and the marker")
+
+	// the marker purged by the other instance is written again.
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1, 5)))
+	require.ElementsMatch(t, []string{
+		"span-1-0", "span-1-1", "span-1-2", "span-1-3", "span-1-4",
+	}, fetchAllSpanNames(t, repo, &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Hour).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	}))
+}
+
+func TestMemoryRepository__BatchWriteFailure(t *testing.T) {
+	cfg := oteleport.DefaultServerConfig()
+	cfg.Storage = oteleport.StorageConfig{
+		Location:            "memory://",
+		CursorEncryptionKey: []byte("r0JwTGIzoOpTi+gH9t+6i/kIwxDi7kR23uwKAeSxxEE="),
+		Batch: oteleport.StorageBatchConfig{
+			Enable:         oteleport.Pointer(true),
+			MaxRecords:     10,
+			MaxBytes:       1024,
+			MaxBufferBytes: 2048,
+			MaxAge:         "1h",
+		},
+	}
+	require.NoError(t, cfg.Storage.Validate(cfg))
+	var failing atomic.Bool
+	repo := oteleport.NewFailingSignalRepository(&cfg.Storage, &failing)
+	ctx := context.Background()
+	fetchReq := &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: uint64(testBaseTime.Add(-time.Minute).UnixNano()),
+		EndTimeUnixNano:   uint64(testBaseTime.Add(time.Hour).UnixNano()),
+	}
+
+	failing.Store(true)
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(0, 5)), "buffered")
+	err := repo.PushTracesData(ctx, newTestTracesData(1, 5))
+	require.Equal(t, codes.Unavailable, status.Code(err), "the full batch failed to write")
+
+	failing.Store(false)
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1, 5)), "retried by the exporter")
+	require.Len(t, fetchAllSpanNames(t, repo, fetchReq), 10, "the previous batch is kept, and the retried data is written once")
+
+	failing.Store(true)
+	for batch := 1; ; batch++ {
+		// a partition per push, so that batches never get full.
+		err := repo.PushTracesData(ctx, newTestTracesData(batch*3600, 1))
+		if err != nil {
+			require.Equal(t, codes.ResourceExhausted, status.Code(err), "rejected when the buffer is full")
+			break
+		}
+		require.Error(t, repo.Flush(ctx), "requeued by flush")
+	}
+	failing.Store(false)
+	require.NoError(t, repo.Flush(ctx))
+	require.NoError(t, repo.PushTracesData(ctx, newTestTracesData(1, 1)), "accepted after the buffer is written")
+}
+
+func TestMemoryRepository__BatchBufferSharedByTenants(t *testing.T) {
+	cfg := oteleport.DefaultServerConfig()
+	cfg.Storage = oteleport.StorageConfig{
+		Location:            "memory://",
+		CursorEncryptionKey: []byte("r0JwTGIzoOpTi+gH9t+6i/kIwxDi7kR23uwKAeSxxEE="),
+		Batch: oteleport.StorageBatchConfig{
+			Enable:         oteleport.Pointer(true),
+			MaxRecords:     10,
+			MaxBytes:       1024,
+			MaxBufferBytes: 2048,
+			MaxAge:         "1h",
+		},
+	}
+	require.NoError(t, cfg.Storage.Validate(cfg))
+	var failing atomic.Bool
+	repo := oteleport.NewFailingSignalRepository(&cfg.Storage, &failing)
+	ctx := context.Background()
+	failing.Store(true)
+	// a partition per push, so that batches never get full.
+	var buffered int
+	for batch := 1; ; batch++ {
+		err := repo.ForTenant("team-a").PushTracesData(ctx, newTestTracesData(batch*3600, 1))
+		if err != nil {
+			require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			break
+		}
+		buffered++
+	}
+	require.Greater(t, buffered, 1)
+	err := repo.ForTenant("team-b").PushTracesData(ctx, newTestTracesData(0, 1))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), "the buffer is shared by tenants")
+
+	failing.Store(false)
+	require.NoError(t, repo.Flush(ctx))
+	require.NoError(t, repo.ForTenant("team-b").PushTracesData(ctx, newTestTracesData(0, 1)), "accepted after buffers of tenants are written")
+}
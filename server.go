@@ -2,6 +2,7 @@ package oteleport
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +27,7 @@ import (
 	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -35,6 +38,7 @@ type Server struct {
 	apiMux      *mux.Router
 	cfg         *ServerConfig
 	signalRepo  SignalRepository
+	jwtVerifier *jwtVerifier
 	TermHandler func()
 }
 
@@ -49,6 +53,9 @@ func NewServer(cfg *ServerConfig) (*Server, error) {
 		return nil, oops.Wrapf(err, "failed to create signal repository")
 	}
 	s.signalRepo = repo
+	if cfg.Auth.JWT.Enabled() {
+		s.jwtVerifier = newJWTVerifier(&cfg.Auth.JWT)
+	}
 	s.setupOTLP()
 	s.setupAPI()
 	return s, nil
@@ -89,19 +96,11 @@ func (s *Server) setupOTLP() {
 				if !ok {
 					return nil, status.Error(codes.Unauthenticated, "no metadata found")
 				}
-				accessKey := header.Get(s.cfg.AccessKeyHeader)
-				if accessKey == "" {
-					slog.InfoContext(ctx, "access denided", "reason", "no access key found")
-					return nil, status.Error(codes.Unauthenticated, "no access key found")
-				}
-				for _, key := range s.cfg.AccessKeys {
-					if key.SecretKey == accessKey {
-						slog.InfoContext(ctx, "authenticated", "key_id", key.KeyID)
-						return next(ctx, req)
-					}
+				key, err := s.authorize(ctx, header.Get(s.cfg.AccessKeyHeader), header.Get("Authorization"), otlpRequiredScope(req))
+				if err != nil {
+					return nil, err
 				}
-				slog.InfoContext(ctx, "access denided", "reason", "access key mismatch")
-				return nil, status.Error(codes.PermissionDenied, "access denied")
+				return next(contextWithAccessKey(ctx, key), req)
 			}
 		})
 	}
@@ -112,6 +111,7 @@ const (
 	fetchTracesPath  = "/traces/fetch"
 	fetchMetricsPath = "/metrics/fetch"
 	fetchLogsPath    = "/logs/fetch"
+	getTracePath     = "/traces/{trace_id}"
 )
 
 func (s *Server) setupAPI() {
@@ -122,6 +122,7 @@ func (s *Server) setupAPI() {
 	base.HandleFunc(fetchTracesPath, s.serveFetchTraces)
 	base.HandleFunc(fetchMetricsPath, s.serveFetchMetrics)
 	base.HandleFunc(fetchLogsPath, s.serveFetchLogs)
+	base.HandleFunc(getTracePath, s.serveGetTrace)
 	base.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			slog.Info("accept api request", "method", r.Method, "path", r.URL.Path, "content_type", r.Header.Get("Content-Type"))
@@ -131,26 +132,106 @@ func (s *Server) setupAPI() {
 	if s.cfg.EnableAuth() {
 		base.Use(func(next http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				header := r.Header.Get(s.cfg.AccessKeyHeader)
-				if header == "" {
-					st := status.New(codes.Unauthenticated, "no access key found")
-					writeError(w, r, st, http.StatusUnsupportedMediaType)
-					return
-				}
-				for _, key := range s.cfg.AccessKeys {
-					if key.SecretKey == header {
-						slog.Info("authenticated", "key_id", key.KeyID)
-						next.ServeHTTP(w, r)
+				key, err := s.authorize(r.Context(), r.Header.Get(s.cfg.AccessKeyHeader), r.Header.Get("Authorization"), apiRequiredScope(r))
+				if err != nil {
+					st := status.Convert(err)
+					if st.Code() == codes.Unauthenticated {
+						writeError(w, r, st, http.StatusUnsupportedMediaType)
 						return
 					}
+					writeError(w, r, st, http.StatusForbidden)
+					return
 				}
-				st := status.New(codes.PermissionDenied, "access denied")
-				writeError(w, r, st, http.StatusForbidden)
+				next.ServeHTTP(w, r.WithContext(contextWithAccessKey(r.Context(), key)))
 			})
 		})
 	}
 }
 
+func (s *Server) lookupClientCertificate(cert *x509.Certificate) (*AccessKeyConfig, bool) {
+	for _, key := range s.cfg.AccessKeys {
+		if key.matchClientCertificate(cert) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// lookupAccessKey checks all access keys, so that the time does not depend on which key matches.
+func (s *Server) lookupAccessKey(accessKey string) (*AccessKeyConfig, bool) {
+	var found *AccessKeyConfig
+	for _, key := range s.cfg.AccessKeys {
+		if key.matchSecretKey(accessKey) && found == nil {
+			found = key
+		}
+	}
+	return found, found != nil
+}
+
+type apiGRPCServer struct {
+	oteleportpb.UnimplementedOterlportServiceServer
+	signalRepo SignalRepository
+}
+
+func (s *apiGRPCServer) FetchTracesData(ctx context.Context, req *oteleportpb.FetchTracesDataRequest) (*oteleportpb.FetchTracesDataResponse, error) {
+	return tenantRepository(ctx, s.signalRepo).FetchTracesData(ctx, req)
+}
+
+func (s *apiGRPCServer) FetchMetricsData(ctx context.Context, req *oteleportpb.FetchMetricsDataRequest) (*oteleportpb.FetchMetricsDataResponse, error) {
+	return tenantRepository(ctx, s.signalRepo).FetchMetricsData(ctx, req)
+}
+
+func (s *apiGRPCServer) FetchLogsData(ctx context.Context, req *oteleportpb.FetchLogsDataRequest) (*oteleportpb.FetchLogsDataResponse, error) {
+	return tenantRepository(ctx, s.signalRepo).FetchLogsData(ctx, req)
+}
+
+func (s *apiGRPCServer) GetTrace(ctx context.Context, req *oteleportpb.GetTraceRequest) (*oteleportpb.GetTraceResponse, error) {
+	return tenantRepository(ctx, s.signalRepo).GetTrace(ctx, req)
+}
+
+func (s *Server) newAPIGRPCServer() (*grpc.Server, error) {
+	interceptors := []grpc.UnaryServerInterceptor{
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			slog.InfoContext(ctx, "accept api request", "method", info.FullMethod)
+			return handler(ctx, req)
+		},
+	}
+	if s.cfg.EnableAuth() {
+		interceptors = append(interceptors, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			md, ok := metadata.FromIncomingContext(ctx)
+			if !ok {
+				return nil, status.Error(codes.Unauthenticated, "no metadata found")
+			}
+			var accessKey, authorization string
+			if values := md.Get(s.cfg.AccessKeyHeader); len(values) > 0 {
+				accessKey = values[0]
+			}
+			if values := md.Get("authorization"); len(values) > 0 {
+				authorization = values[0]
+			}
+			key, err := s.authorize(ctx, accessKey, authorization, apiGRPCRequiredScope(info.FullMethod))
+			if err != nil {
+				return nil, err
+			}
+			return handler(contextWithAccessKey(ctx, key), req)
+		})
+	}
+	grpcOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+	if s.cfg.API.GRPC.TLS.Enabled() {
+		creds, err := serverCredentials(&s.cfg.API.GRPC.TLS)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to setup api grpc tls")
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(creds))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	oteleportpb.RegisterOterlportServiceServer(grpcServer, &apiGRPCServer{
+		signalRepo: s.signalRepo,
+	})
+	reflection.Register(grpcServer)
+	return grpcServer, nil
+}
+
 func (s *Server) runAsLambdaHandler(ctx context.Context) error {
 	apiPathPrefixForLambda := filepath.Join(s.cfg.API.HTTP.Prefix, apiPathPrefix)
 	otlpPathPrefixForLambda := filepath.Join(s.cfg.OTLP.HTTP.Prefix, "/v1")
@@ -188,6 +269,10 @@ func (s *Server) runAsLambdaHandler(ctx context.Context) error {
 		w := ridge.NewResponseWriter()
 		start := time.Now()
 		httpMux.ServeHTTP(w, req)
+		// the execution environment may be frozen after the invocation, so buffered signals are not kept.
+		if err := s.signalRepo.Flush(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to flush buffered signals", "err", err.Error())
+		}
 		resp := w.Response()
 		slog.InfoContext(ctx, "request processed",
 			"method", req.Method,
@@ -227,7 +312,15 @@ func (s *Server) runOnLocalServer(ctx context.Context) error {
 	ctx, cancel := context.WithCancelCause(ctx)
 	defer cancel(nil)
 	if valueOrDefault(s.cfg.OTLP.GRPC.Enable, false) {
-		grpcServer := grpc.NewServer()
+		var grpcOpts []grpc.ServerOption
+		if s.cfg.OTLP.GRPC.TLS.Enabled() {
+			creds, err := serverCredentials(&s.cfg.OTLP.GRPC.TLS)
+			if err != nil {
+				return oops.Wrapf(err, "failed to setup otlp grpc tls")
+			}
+			grpcOpts = append(grpcOpts, grpc.Creds(creds))
+		}
+		grpcServer := grpc.NewServer(grpcOpts...)
 		s.otlpMux.Register(grpcServer)
 		reflection.Register(grpcServer)
 		grpcListener := s.cfg.OTLP.GRPC.Listener
@@ -249,7 +342,7 @@ func (s *Server) runOnLocalServer(ctx context.Context) error {
 		}
 		httpServer := &http.Server{
 			Addr:    s.cfg.OTLP.HTTP.Address,
-			Handler: httpMux,
+			Handler: withClientCertificate(httpMux),
 		}
 		httpListener := s.cfg.OTLP.HTTP.Listener
 		if httpListener == nil {
@@ -259,6 +352,10 @@ func (s *Server) runOnLocalServer(ctx context.Context) error {
 				return oops.Wrapf(err, "failed to listen to %s", s.cfg.OTLP.HTTP.Address)
 			}
 		}
+		httpListener, err := newTLSListener(httpListener, &s.cfg.OTLP.HTTP.TLS)
+		if err != nil {
+			return oops.Wrapf(err, "failed to setup otlp http tls")
+		}
 		cleanups = append(cleanups, startHTTPServer(&wg, ctx, cancel, httpServer, httpListener, "otlp"))
 	}
 
@@ -267,7 +364,7 @@ func (s *Server) runOnLocalServer(ctx context.Context) error {
 		httpMux.Handle("/", s.apiMux)
 		server := &http.Server{
 			Addr:    s.cfg.API.HTTP.Address,
-			Handler: httpMux,
+			Handler: withClientCertificate(httpMux),
 		}
 		httpListener := s.cfg.API.HTTP.Listener
 		if httpListener == nil {
@@ -277,8 +374,46 @@ func (s *Server) runOnLocalServer(ctx context.Context) error {
 				return oops.Wrapf(err, "failed to listen to %s", s.cfg.API.HTTP.Address)
 			}
 		}
+		httpListener, err := newTLSListener(httpListener, &s.cfg.API.HTTP.TLS)
+		if err != nil {
+			return oops.Wrapf(err, "failed to setup api http tls")
+		}
 		cleanups = append(cleanups, startHTTPServer(&wg, ctx, cancel, server, httpListener, "api"))
 	}
+	if valueOrDefault(s.cfg.API.GRPC.Enable, false) {
+		grpcServer, err := s.newAPIGRPCServer()
+		if err != nil {
+			return err
+		}
+		grpcListener := s.cfg.API.GRPC.Listener
+		if grpcListener == nil {
+			var err error
+			grpcListener, err = net.Listen("tcp", s.cfg.API.GRPC.Address)
+			if err != nil {
+				return oops.Wrapf(err, "failed to listen to %s", s.cfg.API.GRPC.Address)
+			}
+		}
+		cleanups = append(cleanups, startGRPCServer(&wg, ctx, cancel, grpcServer, grpcListener, "api"))
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.signalRepo.RunFlushLoop(ctx)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.signalRepo.RunPurgeLoop(ctx)
+	}()
+	// buffered signals are written after all servers are stopped.
+	cleanups = append(cleanups, func(ctx context.Context) {
+		fCtx, fCancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer fCancel()
+		slog.InfoContext(ctx, "flushing buffered signals")
+		if err := s.signalRepo.Flush(fCtx); err != nil {
+			slog.ErrorContext(ctx, "failed to flush buffered signals", "err", err.Error())
+		}
+	})
 	wg.Add(1)
 	go func() {
 		<-ctx.Done()
@@ -335,12 +470,12 @@ func startHTTPServer(wg *sync.WaitGroup, ctx context.Context, cancel context.Can
 func (s *Server) handleTraces(ctx context.Context, req *otlp.TraceRequest) (*otlp.TraceResponse, error) {
 	resourceSpans := req.GetResourceSpans()
 	slog.Info("received otlp trace", "total_spans", otlp.TotalSpans(resourceSpans))
-	if err := s.signalRepo.PushTracesData(ctx, &tracepb.TracesData{
+	if err := tenantRepository(ctx, s.signalRepo).PushTracesData(ctx, &tracepb.TracesData{
 		ResourceSpans: resourceSpans,
 	}); err != nil {
 		errID := RandomString(16)
 		slog.Error("failed to put resource spans", "err_id", errID, "details", err.Error())
-		return nil, fmt.Errorf("failed to put resource spans: error_id=%s", errID)
+		return nil, pushError(err, "failed to put resource spans: error_id=%s", errID)
 	}
 	return &otlp.TraceResponse{}, nil
 }
@@ -348,12 +483,12 @@ func (s *Server) handleTraces(ctx context.Context, req *otlp.TraceRequest) (*otl
 func (s *Server) handleMetrics(ctx context.Context, req *otlp.MetricsRequest) (*otlp.MetricsResponse, error) {
 	resourceMetrics := req.GetResourceMetrics()
 	slog.Info("received otlp metrics", "total_data_points", otlp.TotalDataPoints(resourceMetrics))
-	if err := s.signalRepo.PushMetricsData(ctx, &metricspb.MetricsData{
+	if err := tenantRepository(ctx, s.signalRepo).PushMetricsData(ctx, &metricspb.MetricsData{
 		ResourceMetrics: resourceMetrics,
 	}); err != nil {
 		errID := RandomString(16)
 		slog.Error("failed to put resource metrics", "err_id", errID, "details", err.Error())
-		return nil, fmt.Errorf("failed to put resource metrics: error_id=%s", errID)
+		return nil, pushError(err, "failed to put resource metrics: error_id=%s", errID)
 	}
 	return &otlp.MetricsResponse{}, nil
 }
@@ -361,16 +496,24 @@ func (s *Server) handleMetrics(ctx context.Context, req *otlp.MetricsRequest) (*
 func (s *Server) handleLogs(ctx context.Context, req *otlp.LogsRequest) (*otlp.LogsResponse, error) {
 	resourceLogs := req.GetResourceLogs()
 	slog.Info("received otlp logs", "total_log_records", otlp.TotalLogRecords(resourceLogs))
-	if err := s.signalRepo.PushLogsData(ctx, &logspb.LogsData{
+	if err := tenantRepository(ctx, s.signalRepo).PushLogsData(ctx, &logspb.LogsData{
 		ResourceLogs: resourceLogs,
 	}); err != nil {
 		errID := RandomString(16)
 		slog.Error("failed to put resource logs", "err_id", errID, "details", err.Error())
-		return nil, fmt.Errorf("failed to put resource logs: error_id=%s", errID)
+		return nil, pushError(err, "failed to put resource logs: error_id=%s", errID)
 	}
 	return &otlp.LogsResponse{}, nil
 }
 
+// pushError keeps the status code of the push error, like Unavailable and ResourceExhausted, so that exporters retry the request.
+func pushError(err error, format string, args ...any) error {
+	if st, ok := status.FromError(err); ok {
+		return status.Errorf(st.Code(), format, args...)
+	}
+	return fmt.Errorf(format, args...)
+}
+
 func parseRequest[T proto.Message](r *http.Request, v T) error {
 	bs, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -510,7 +653,7 @@ func (s *Server) serveFetchTraces(w http.ResponseWriter, r *http.Request) {
 		writeError(w, r, st, http.StatusBadRequest)
 		return
 	}
-	resp, err := s.signalRepo.FetchTracesData(ctx, req)
+	resp, err := tenantRepository(ctx, s.signalRepo).FetchTracesData(ctx, req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if !ok {
@@ -540,7 +683,7 @@ func (s *Server) serveFetchMetrics(w http.ResponseWriter, r *http.Request) {
 		writeError(w, r, st, http.StatusBadRequest)
 		return
 	}
-	resp, err := s.signalRepo.FetchMetricsData(ctx, req)
+	resp, err := tenantRepository(ctx, s.signalRepo).FetchMetricsData(ctx, req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if !ok {
@@ -570,7 +713,7 @@ func (s *Server) serveFetchLogs(w http.ResponseWriter, r *http.Request) {
 		writeError(w, r, st, http.StatusBadRequest)
 		return
 	}
-	resp, err := s.signalRepo.FetchLogsData(ctx, req)
+	resp, err := tenantRepository(ctx, s.signalRepo).FetchLogsData(ctx, req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if !ok {
@@ -581,3 +724,47 @@ func (s *Server) serveFetchLogs(w http.ResponseWriter, r *http.Request) {
 	}
 	writeResponse(w, r, resp)
 }
+
+func (s *Server) serveGetTrace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodGet {
+		st := status.New(codes.Unimplemented, "method not allowed")
+		writeError(w, r, st, http.StatusMethodNotAllowed)
+		return
+	}
+	req := &oteleportpb.GetTraceRequest{
+		TraceId: mux.Vars(r)["trace_id"],
+	}
+	query := r.URL.Query()
+	for name, field := range map[string]*uint64{
+		"start_time_unix_nano": &req.StartTimeUnixNano,
+		"end_time_unix_nano":   &req.EndTimeUnixNano,
+	} {
+		if str := query.Get(name); str != "" {
+			v, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid %s", name))
+				writeError(w, r, st, http.StatusBadRequest)
+				return
+			}
+			*field = v
+		}
+	}
+	resp, err := tenantRepository(ctx, s.signalRepo).GetTrace(ctx, req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if !ok {
+			st = status.New(codes.Internal, err.Error())
+		}
+		code := http.StatusInternalServerError
+		switch st.Code() {
+		case codes.InvalidArgument:
+			code = http.StatusBadRequest
+		case codes.NotFound:
+			code = http.StatusNotFound
+		}
+		writeError(w, r, st, code)
+		return
+	}
+	writeResponse(w, r, resp)
+}
@@ -3,31 +3,84 @@ package oteleport_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/mashiike/go-otlp-helper/otlp"
 	"github.com/mashiike/oteleport"
+	oteleportclient "github.com/mashiike/oteleport/pkg/client"
 	oteleportpb "github.com/mashiike/oteleport/proto"
 	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
 	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
 	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestServer__Trace(t *testing.T) {
-	testcaseServer__Trace(t, false)
+	testcaseServer__Trace(t, "", false)
 }
 
 func TestServer__Trace__Flatten(t *testing.T) {
-	testcaseServer__Trace(t, true)
+	testcaseServer__Trace(t, "", true)
 }
 
-func testcaseServer__Trace(t *testing.T, flatten bool) {
+func TestServer__Trace__File(t *testing.T) {
+	testcaseServer__Trace(t, "file://"+t.TempDir(), false)
+}
+
+func TestServer__Trace__File__Flatten(t *testing.T) {
+	testcaseServer__Trace(t, "file://"+t.TempDir(), true)
+}
+
+func TestServer__Trace__File__Parquet(t *testing.T) {
+	testcaseServer__Trace(t, "file://"+t.TempDir(), true, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatParquet
+	})
+}
+
+func TestServer__Trace__File__Protobuf(t *testing.T) {
+	testcaseServer__Trace(t, "file://"+t.TempDir(), false, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatProtobuf
+	})
+}
+
+func TestServer__Trace__File__Protobuf__Flatten(t *testing.T) {
+	testcaseServer__Trace(t, "file://"+t.TempDir(), true, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatProtobuf
+	})
+}
+
+func TestServer__Trace__Memory(t *testing.T) {
+	testcaseServer__Trace(t, "memory://", false)
+}
+
+func testcaseServer__Trace(t *testing.T, location string, flatten bool, storageOpts ...func(*oteleport.StorageConfig)) {
 	cfg := oteleport.DefaultServerConfig()
 	err := cfg.Load("testdata/default.jsonnet", nil)
 	require.NoError(t, err)
@@ -38,8 +91,15 @@ func testcaseServer__Trace(t *testing.T, flatten bool) {
 	require.NoError(t, err)
 	cfg.OTLP.HTTP.Enable = oteleport.Pointer(false)
 	cfg.API.HTTP.Listener = httpOTLPLis
-	cfg.Storage.Location += oteleport.RandomString(12)
+	if location != "" {
+		cfg.Storage.Location = location
+	} else {
+		cfg.Storage.Location += oteleport.RandomString(12)
+	}
 	cfg.Storage.Flatten = oteleport.Pointer(flatten)
+	for _, opt := range storageOpts {
+		opt(&cfg.Storage)
+	}
 	err = cfg.Validate()
 	require.NoError(t, err)
 
@@ -99,19 +159,123 @@ func testcaseServer__Trace(t *testing.T, flatten bool) {
 	require.NoError(t, err)
 	require.JSONEq(t, string(expectedJSON), string(acutalJSON))
 
+	// get trace
+	apiClient, err := oteleportclient.New(&oteleportclient.Profile{
+		Endpoint: "http://" + cfg.API.HTTP.Address,
+	})
+	require.NoError(t, err)
+	getResp, err := apiClient.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           "5B8EFFF798038103D269B633813FC60C",
+		StartTimeUnixNano: 1544712660000000000,
+		EndTimeUnixNano:   1544712661000000000,
+	})
+	require.NoError(t, err)
+	acutalJSON, err = otlp.MarshalJSON(&tracepb.TracesData{
+		ResourceSpans: getResp.GetResourceSpans(),
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(acutalJSON))
+	_, err = apiClient.GetTrace(ctx, &oteleportpb.GetTraceRequest{
+		TraceId:           "00000000000000000000000000000000",
+		StartTimeUnixNano: 1544712660000000000,
+		EndTimeUnixNano:   1544712661000000000,
+	})
+	require.ErrorContains(t, err, "NotFound")
+
+	cancel()
+	wg.Wait()
+}
+
+func TestServer__Batch__FlushOnShutdown(t *testing.T) {
+	cfg := oteleport.DefaultServerConfig()
+	require.NoError(t, cfg.Load("testdata/default.jsonnet", nil))
+	grpcOTLPLis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg.OTLP.GRPC.Listener = grpcOTLPLis
+	cfg.OTLP.HTTP.Enable = oteleport.Pointer(false)
+	httpAPILis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg.API.HTTP.Listener = httpAPILis
+	location := "file://" + t.TempDir()
+	cfg.Storage.Location = location
+	cfg.Storage.Batch = oteleport.StorageBatchConfig{
+		Enable: oteleport.Pointer(true),
+		MaxAge: "1h",
+	}
+	require.NoError(t, cfg.Validate())
+	s, err := oteleport.NewServer(cfg)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer wg.Done()
+		err := s.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	}()
+
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	client, err := otlp.NewClient("http://" + cfg.OTLP.GRPC.Address)
+	require.NoError(t, err)
+	require.NoError(t, client.Start(ctx))
+	require.NoError(t, client.UploadTraces(ctx, traces.GetResourceSpans()))
+	require.NoError(t, client.Stop(ctx))
 	cancel()
 	wg.Wait()
+
+	repo := newTestRepository(t, oteleport.StorageConfig{
+		Location: location,
+	})
+	resp, err := repo.FetchTracesData(context.Background(), &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: 1544712660000000000,
+		EndTimeUnixNano:   1544712661000000000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, otlp.TotalSpans(traces.GetResourceSpans()), otlp.TotalSpans(resp.GetResourceSpans()))
 }
 
 func TestServer__Metrics(t *testing.T) {
-	testcaseServer__Metrics(t, false)
+	testcaseServer__Metrics(t, "", false)
 }
 
 func TestServer__Metrics__Flatten(t *testing.T) {
-	testcaseServer__Metrics(t, true)
+	testcaseServer__Metrics(t, "", true)
+}
+
+func TestServer__Metrics__File(t *testing.T) {
+	testcaseServer__Metrics(t, "file://"+t.TempDir(), false)
 }
 
-func testcaseServer__Metrics(t *testing.T, flatten bool) {
+func TestServer__Metrics__File__Flatten(t *testing.T) {
+	testcaseServer__Metrics(t, "file://"+t.TempDir(), true)
+}
+
+func TestServer__Metrics__File__Parquet(t *testing.T) {
+	testcaseServer__Metrics(t, "file://"+t.TempDir(), true, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatParquet
+	})
+}
+
+func TestServer__Metrics__File__Protobuf(t *testing.T) {
+	testcaseServer__Metrics(t, "file://"+t.TempDir(), false, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatProtobuf
+	})
+}
+
+func TestServer__Metrics__File__Protobuf__Flatten(t *testing.T) {
+	testcaseServer__Metrics(t, "file://"+t.TempDir(), true, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatProtobuf
+	})
+}
+
+func TestServer__Metrics__Memory(t *testing.T) {
+	testcaseServer__Metrics(t, "memory://", false)
+}
+
+func testcaseServer__Metrics(t *testing.T, location string, flatten bool, storageOpts ...func(*oteleport.StorageConfig)) {
 	cfg := oteleport.DefaultServerConfig()
 	err := cfg.Load("testdata/default.jsonnet", nil)
 	require.NoError(t, err)
@@ -122,8 +286,15 @@ func testcaseServer__Metrics(t *testing.T, flatten bool) {
 	require.NoError(t, err)
 	cfg.OTLP.HTTP.Enable = oteleport.Pointer(false)
 	cfg.API.HTTP.Listener = httpOTLPLis
-	cfg.Storage.Location += oteleport.RandomString(12)
+	if location != "" {
+		cfg.Storage.Location = location
+	} else {
+		cfg.Storage.Location += oteleport.RandomString(12)
+	}
 	cfg.Storage.Flatten = oteleport.Pointer(flatten)
+	for _, opt := range storageOpts {
+		opt(&cfg.Storage)
+	}
 	err = cfg.Validate()
 	require.NoError(t, err)
 
@@ -188,14 +359,44 @@ func testcaseServer__Metrics(t *testing.T, flatten bool) {
 }
 
 func TestServer__Logs(t *testing.T) {
-	testcaseServer__Logs(t, false)
+	testcaseServer__Logs(t, "", false)
 }
 
 func TestServer__Logs__Flatten(t *testing.T) {
-	testcaseServer__Logs(t, true)
+	testcaseServer__Logs(t, "", true)
+}
+
+func TestServer__Logs__File(t *testing.T) {
+	testcaseServer__Logs(t, "file://"+t.TempDir(), false)
 }
 
-func testcaseServer__Logs(t *testing.T, flatten bool) {
+func TestServer__Logs__File__Flatten(t *testing.T) {
+	testcaseServer__Logs(t, "file://"+t.TempDir(), true)
+}
+
+func TestServer__Logs__File__Parquet(t *testing.T) {
+	testcaseServer__Logs(t, "file://"+t.TempDir(), true, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatParquet
+	})
+}
+
+func TestServer__Logs__File__Protobuf(t *testing.T) {
+	testcaseServer__Logs(t, "file://"+t.TempDir(), false, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatProtobuf
+	})
+}
+
+func TestServer__Logs__File__Protobuf__Flatten(t *testing.T) {
+	testcaseServer__Logs(t, "file://"+t.TempDir(), true, func(c *oteleport.StorageConfig) {
+		c.Format = oteleport.StorageFormatProtobuf
+	})
+}
+
+func TestServer__Logs__Memory(t *testing.T) {
+	testcaseServer__Logs(t, "memory://", false)
+}
+
+func testcaseServer__Logs(t *testing.T, location string, flatten bool, storageOpts ...func(*oteleport.StorageConfig)) {
 	cfg := oteleport.DefaultServerConfig()
 	err := cfg.Load("testdata/default.jsonnet", nil)
 	require.NoError(t, err)
@@ -206,8 +407,15 @@ func testcaseServer__Logs(t *testing.T, flatten bool) {
 	require.NoError(t, err)
 	cfg.OTLP.HTTP.Enable = oteleport.Pointer(false)
 	cfg.API.HTTP.Listener = httpOTLPLis
-	cfg.Storage.Location += oteleport.RandomString(12)
+	if location != "" {
+		cfg.Storage.Location = location
+	} else {
+		cfg.Storage.Location += oteleport.RandomString(12)
+	}
 	cfg.Storage.Flatten = oteleport.Pointer(flatten)
+	for _, opt := range storageOpts {
+		opt(&cfg.Storage)
+	}
 	err = cfg.Validate()
 	require.NoError(t, err)
 
@@ -270,3 +478,539 @@ func testcaseServer__Logs(t *testing.T, flatten bool) {
 	cancel()
 	wg.Wait()
 }
+
+func TestServer__APIGRPC(t *testing.T) {
+	cfg := oteleport.DefaultServerConfig()
+	err := cfg.Load("testdata/with_access_key.jsonnet", nil)
+	require.NoError(t, err)
+	grpcOTLPLis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg.OTLP.GRPC.Listener = grpcOTLPLis
+	cfg.OTLP.HTTP.Enable = oteleport.Pointer(false)
+	cfg.API.HTTP.Enable = oteleport.Pointer(false)
+	grpcAPILis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg.API.GRPC.Enable = oteleport.Pointer(true)
+	cfg.API.GRPC.Listener = grpcAPILis
+	cfg.Storage.Location = "memory://"
+	err = cfg.Validate()
+	require.NoError(t, err)
+
+	s, err := oteleport.NewServer(cfg)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer wg.Done()
+		err := s.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	}()
+
+	// upload trace
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	client, err := otlp.NewClient("http://"+cfg.OTLP.GRPC.Address, otlp.WithHeaders(map[string]string{
+		cfg.AccessKeyHeader: "oteleport0000",
+	}))
+	require.NoError(t, err)
+	err = client.Start(ctx)
+	require.NoError(t, err)
+	err = client.UploadTraces(ctx, traces.GetResourceSpans())
+	require.NoError(t, err)
+	err = client.Stop(ctx)
+	require.NoError(t, err)
+
+	// fetch trace via gRPC API
+	conn, err := grpc.NewClient(cfg.API.GRPC.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	apiClient := oteleportpb.NewOterlportServiceClient(conn)
+	reqBody := &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: 1544712660000000000,
+		EndTimeUnixNano:   1544712661000000000,
+	}
+	_, err = apiClient.FetchTracesData(ctx, reqBody)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = apiClient.FetchTracesData(metadata.AppendToOutgoingContext(ctx, cfg.AccessKeyHeader, "invalid"), reqBody)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	respData, err := apiClient.FetchTracesData(metadata.AppendToOutgoingContext(ctx, cfg.AccessKeyHeader, "oteleport0000"), reqBody)
+	require.NoError(t, err)
+	require.False(t, respData.GetHasMore())
+	acutalJSON, err := otlp.MarshalJSON(&tracepb.TracesData{
+		ResourceSpans: respData.GetResourceSpans(),
+	})
+	require.NoError(t, err)
+	expectedJSON, err := otlp.MarshalJSON(&traces)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(acutalJSON))
+
+	cancel()
+	wg.Wait()
+}
+
+func TestServer__AccessKeyScopes(t *testing.T) {
+	ctx := context.Background()
+	cfg := startTestMemoryServer(t, ctx, func(cfg *oteleport.ServerConfig) {
+		cfg.AccessKeys = []*oteleport.AccessKeyConfig{
+			{KeyID: "sdk", SecretKey: "sdk-secret", Scopes: []string{oteleport.ScopeOTLPWrite}},
+			{KeyID: "traces-reader", SecretKey: "reader-secret", Scopes: []string{oteleport.ScopeAPIReadTraces}},
+			{KeyID: "admin", SecretKey: "admin-secret"},
+		}
+	})
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	upload := func(accessKey string) error {
+		client, err := otlp.NewClient("http://"+cfg.OTLP.GRPC.Address, otlp.WithHeaders(map[string]string{
+			cfg.AccessKeyHeader: accessKey,
+		}))
+		require.NoError(t, err)
+		require.NoError(t, client.Start(ctx))
+		defer client.Stop(ctx)
+		return client.UploadTraces(ctx, traces.GetResourceSpans())
+	}
+	require.Equal(t, codes.PermissionDenied, status.Code(upload("reader-secret")))
+	require.NoError(t, upload("sdk-secret"))
+
+	newAPIClient := func(accessKey string) *oteleportclient.Client {
+		client, err := oteleportclient.New(&oteleportclient.Profile{
+			Endpoint:        "http://" + cfg.API.HTTP.Address,
+			AccessKey:       accessKey,
+			AccessKeyHeader: cfg.AccessKeyHeader,
+		})
+		require.NoError(t, err)
+		return client
+	}
+	tracesReq := &oteleportpb.FetchTracesDataRequest{
+		StartTimeUnixNano: 1544712660000000000,
+		EndTimeUnixNano:   1544712661000000000,
+	}
+	logsReq := &oteleportpb.FetchLogsDataRequest{
+		StartTimeUnixNano: 1544712660000000000,
+		EndTimeUnixNano:   1544712661000000000,
+	}
+	_, err = newAPIClient("sdk-secret").FetchTracesData(ctx, tracesReq)
+	require.Error(t, err, "otlp:write key can not read")
+	resp, err := newAPIClient("reader-secret").FetchTracesData(ctx, tracesReq)
+	require.NoError(t, err)
+	require.Equal(t, otlp.TotalSpans(traces.GetResourceSpans()), otlp.TotalSpans(resp.GetResourceSpans()))
+	_, err = newAPIClient("reader-secret").FetchLogsData(ctx, logsReq)
+	require.Error(t, err, "api:read:traces key can not read logs")
+	_, err = newAPIClient("admin-secret").FetchLogsData(ctx, logsReq)
+	require.NoError(t, err, "key without scopes is granted all scopes")
+}
+
+func TestServer__AccessKeyTenants(t *testing.T) {
+	ctx := context.Background()
+	cfg := startTestMemoryServer(t, ctx, func(cfg *oteleport.ServerConfig) {
+		cfg.AccessKeys = []*oteleport.AccessKeyConfig{
+			{KeyID: "team-a", SecretKey: "team-a-secret", Tenant: "team-a"},
+			{KeyID: "team-b", SecretKey: "team-b-secret", Tenant: "team-b"},
+			{KeyID: "default", SecretKey: "default-secret"},
+		}
+	})
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	client, err := otlp.NewClient("http://"+cfg.OTLP.GRPC.Address, otlp.WithHeaders(map[string]string{
+		cfg.AccessKeyHeader: "team-a-secret",
+	}))
+	require.NoError(t, err)
+	require.NoError(t, client.Start(ctx))
+	defer client.Stop(ctx)
+	require.NoError(t, client.UploadTraces(ctx, traces.GetResourceSpans()))
+
+	fetch := func(accessKey string) int {
+		apiClient, err := oteleportclient.New(&oteleportclient.Profile{
+			Endpoint:        "http://" + cfg.API.HTTP.Address,
+			AccessKey:       accessKey,
+			AccessKeyHeader: cfg.AccessKeyHeader,
+		})
+		require.NoError(t, err)
+		resp, err := apiClient.FetchTracesData(ctx, &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: 1544712660000000000,
+			EndTimeUnixNano:   1544712661000000000,
+		})
+		require.NoError(t, err)
+		return otlp.TotalSpans(resp.GetResourceSpans())
+	}
+	require.Equal(t, otlp.TotalSpans(traces.GetResourceSpans()), fetch("team-a-secret"))
+	require.Equal(t, 0, fetch("team-b-secret"), "tenant can not see signals of other tenants")
+	require.Equal(t, 0, fetch("default-secret"), "key without tenant can not see signals of tenants")
+}
+
+func TestServer__AccessKeyHash(t *testing.T) {
+	ctx := context.Background()
+	hash, err := oteleport.HashSecretKey("hashed-secret")
+	require.NoError(t, err)
+	cfg := startTestMemoryServer(t, ctx, func(cfg *oteleport.ServerConfig) {
+		cfg.AccessKeys = []*oteleport.AccessKeyConfig{
+			{KeyID: "hashed", SecretKeyHash: hash},
+		}
+	})
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	upload := func(accessKey string) error {
+		client, err := otlp.NewClient("http://"+cfg.OTLP.GRPC.Address, otlp.WithHeaders(map[string]string{
+			cfg.AccessKeyHeader: accessKey,
+		}))
+		require.NoError(t, err)
+		require.NoError(t, client.Start(ctx))
+		defer client.Stop(ctx)
+		return client.UploadTraces(ctx, traces.GetResourceSpans())
+	}
+	require.NoError(t, upload("hashed-secret"))
+	require.Equal(t, codes.PermissionDenied, status.Code(upload(hash)), "the hash itself is not a usable secret")
+	require.Equal(t, codes.PermissionDenied, status.Code(upload("wrong-secret")))
+}
+
+func TestServer__JWT(t *testing.T) {
+	ctx := context.Background()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			},
+		},
+	})
+	require.NoError(t, err)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, jwks, 0644))
+	cfg := startTestMemoryServer(t, ctx, func(cfg *oteleport.ServerConfig) {
+		cfg.Auth.JWT = oteleport.AuthJWTConfig{
+			JWKS:        jwksPath,
+			Issuer:      "https://idp.example.com",
+			Audience:    "oteleport",
+			TenantClaim: "team",
+			ScopeMapping: map[string][]string{
+				"telemetry.write": {oteleport.ScopeOTLPWrite},
+			},
+		}
+	})
+	sign := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(privateKey)
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+	claims := func(scope string, overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   "https://idp.example.com",
+			"aud":   "oteleport",
+			"sub":   "alice",
+			"team":  "team-a",
+			"scope": scope,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	upload := func(authorization string) error {
+		client, err := otlp.NewClient("http://"+cfg.OTLP.GRPC.Address, otlp.WithHeaders(map[string]string{
+			"Authorization": authorization,
+		}))
+		require.NoError(t, err)
+		require.NoError(t, client.Start(ctx))
+		defer client.Stop(ctx)
+		return client.UploadTraces(ctx, traces.GetResourceSpans())
+	}
+	require.Equal(t, codes.PermissionDenied, status.Code(upload(sign(claims("telemetry.write", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})))), "expired")
+	require.Equal(t, codes.PermissionDenied, status.Code(upload(sign(claims("telemetry.write", jwt.MapClaims{"aud": "other"})))), "wrong audience")
+	require.Equal(t, codes.PermissionDenied, status.Code(upload(sign(claims("telemetry.write", jwt.MapClaims{"iss": "https://other.example.com"})))), "wrong issuer")
+	require.Equal(t, codes.PermissionDenied, status.Code(upload(sign(claims("openid profile", nil)))), "no scopes")
+	require.NoError(t, upload(sign(claims("openid telemetry.write", nil))))
+
+	fetch := func(authorization string) (int, error) {
+		apiClient, err := oteleportclient.New(&oteleportclient.Profile{
+			Endpoint:        "http://" + cfg.API.HTTP.Address,
+			AccessKey:       authorization,
+			AccessKeyHeader: "Authorization",
+		})
+		require.NoError(t, err)
+		resp, err := apiClient.FetchTracesData(ctx, &oteleportpb.FetchTracesDataRequest{
+			StartTimeUnixNano: 1544712660000000000,
+			EndTimeUnixNano:   1544712661000000000,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return otlp.TotalSpans(resp.GetResourceSpans()), nil
+	}
+	_, err = fetch(sign(claims("telemetry.write", nil)))
+	require.Error(t, err, "otlp:write token can not read")
+	n, err := fetch(sign(claims("api:read", nil)))
+	require.NoError(t, err)
+	require.Equal(t, otlp.TotalSpans(traces.GetResourceSpans()), n)
+	n, err = fetch(sign(claims("api:read", jwt.MapClaims{"team": "team-b"})))
+	require.NoError(t, err)
+	require.Equal(t, 0, n, "tenant of the claim can not see signals of other tenants")
+}
+
+func TestServer__JWTRefresh(t *testing.T) {
+	ctx := context.Background()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "test-key",
+				"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			},
+		},
+	})
+	require.NoError(t, err)
+	// the JWKS endpoint hangs after the first response, like an unhealthy IdP.
+	hang := make(chan struct{})
+	var mu sync.Mutex
+	requests := 0
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		mu.Unlock()
+		if !first {
+			select {
+			case <-hang:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Write(jwks)
+	}))
+	t.Cleanup(jwksServer.Close)
+	t.Cleanup(func() { close(hang) })
+	cfg := startTestMemoryServer(t, ctx, func(cfg *oteleport.ServerConfig) {
+		cfg.Auth.JWT = oteleport.AuthJWTConfig{
+			JWKS:         jwksServer.URL,
+			JWKSCacheTTL: "100ms",
+			Issuer:       "https://idp.example.com",
+			Audience:     "oteleport",
+		}
+	})
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   "https://idp.example.com",
+		"aud":   "oteleport",
+		"sub":   "alice",
+		"scope": oteleport.ScopeOTLPWrite,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	upload := func() error {
+		uploadCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+		client, err := otlp.NewClient("http://"+cfg.OTLP.GRPC.Address, otlp.WithHeaders(map[string]string{
+			"Authorization": "Bearer " + signed,
+		}))
+		require.NoError(t, err)
+		require.NoError(t, client.Start(uploadCtx))
+		defer client.Stop(ctx)
+		return client.UploadTraces(uploadCtx, traces.GetResourceSpans())
+	}
+	require.NoError(t, upload())
+	time.Sleep(200 * time.Millisecond)
+	// the cached keys are used while the expired JWKS is being loaded.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, upload())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 2, requests, "the JWKS is loaded by one request at a time")
+}
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, commonName string, serial int64, parent *testCertificate) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCertificate{cert: cert, key: key}
+}
+
+func (c *testCertificate) writeFiles(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0644))
+	if keyFile == "" {
+		return
+	}
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+func TestServer__TLS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "test-ca", 1, nil)
+	ca.writeFiles(t, filepath.Join(dir, "ca.pem"), "")
+	newTestCertificate(t, "localhost", 2, ca).writeFiles(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"))
+	collector := newTestCertificate(t, "collector", 3, ca)
+	tlsCfg := oteleport.TLSConfig{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+		MinVersion:   "1.2",
+	}
+	cfg := startTestMemoryServer(t, ctx, func(cfg *oteleport.ServerConfig) {
+		cfg.OTLP.GRPC.TLS = tlsCfg
+		cfg.API.HTTP.TLS = tlsCfg
+		cfg.AccessKeys = []*oteleport.AccessKeyConfig{
+			{KeyID: "collector", ClientCertSubject: "collector", Scopes: []string{oteleport.ScopeOTLPWrite}},
+			{KeyID: "reader", SecretKey: "reader-secret", Scopes: []string{oteleport.ScopeAPIRead}},
+		}
+	})
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+	localhost := func(address string) string {
+		_, port, err := net.SplitHostPort(address)
+		require.NoError(t, err)
+		return net.JoinHostPort("localhost", port)
+	}
+	clientTLS := func(certs ...tls.Certificate) *tls.Config {
+		return &tls.Config{RootCAs: rootCAs, Certificates: certs}
+	}
+
+	bs, err := os.ReadFile("testdata/trace.json")
+	require.NoError(t, err)
+	var traces tracepb.TracesData
+	require.NoError(t, otlp.UnmarshalJSON(bs, &traces))
+	export := func(tlsCfg *tls.Config) error {
+		conn, err := grpc.NewClient(localhost(cfg.OTLP.GRPC.Address), grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = coltracepb.NewTraceServiceClient(conn).Export(ctx, &coltracepb.ExportTraceServiceRequest{
+			ResourceSpans: traces.GetResourceSpans(),
+		})
+		return err
+	}
+	require.Equal(t, codes.Unauthenticated, status.Code(export(clientTLS())), "no client certificate and no access key")
+	require.NoError(t, export(clientTLS(collector.tlsCertificate())))
+
+	fetch := func(tlsCfg *tls.Config, accessKey string) *http.Response {
+		body, err := json.Marshal(map[string]any{
+			"startTimeUnixNano": 1544712660000000000,
+			"endTimeUnixNano":   1544712661000000000,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "https://"+localhost(cfg.API.HTTP.Address)+"/api/traces/fetch", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if accessKey != "" {
+			req.Header.Set(cfg.AccessKeyHeader, accessKey)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	require.Equal(t, http.StatusForbidden, fetch(clientTLS(collector.tlsCertificate()), "").StatusCode, "collector certificate can not read")
+	resp := fetch(clientTLS(), "reader-secret")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var fetched oteleportpb.FetchTracesDataResponse
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, otlp.UnmarshalJSON(respBody, &fetched))
+	require.Equal(t, otlp.TotalSpans(traces.GetResourceSpans()), otlp.TotalSpans(fetched.GetResourceSpans()))
+
+	// the renewed certificate is used without restarting.
+	newTestCertificate(t, "localhost", 4, ca).writeFiles(t, filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "server.pem"), future, future))
+	// the files are checked at most once per second.
+	require.Eventually(t, func() bool {
+		conn, err := tls.Dial("tcp", localhost(cfg.API.HTTP.Address), clientTLS())
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64() == 4
+	}, 3*time.Second, 100*time.Millisecond)
+}
+
+func TestTLSReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "test-ca", 1, nil)
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	newTestCertificate(t, "localhost", 2, ca).writeFiles(t, certFile, keyFile)
+	r, err := oteleport.NewTLSReloader(&oteleport.TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	require.True(t, r.CheckDue())
+	require.False(t, r.CheckDue(), "the files are not checked again within the interval")
+
+	touch := func(d time.Duration) {
+		modTime := time.Now().Add(d)
+		require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	}
+	require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0644))
+	touch(time.Minute)
+	_, err = r.Reload()
+	require.Error(t, err)
+	changed, err := r.Reload()
+	require.NoError(t, err, "the failure is reported once until the files are changed")
+	require.False(t, changed)
+	touch(2 * time.Minute)
+	_, err = r.Reload()
+	require.Error(t, err, "the changed files are loaded again")
+
+	newTestCertificate(t, "localhost", 3, ca).writeFiles(t, certFile, keyFile)
+	touch(3 * time.Minute)
+	changed, err = r.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+}
@@ -0,0 +1,30 @@
+package oteleport
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// objectStorage is a key-value store for signal objects.
+// keys are slash separated paths, and listing returns objects in lexical order.
+type objectStorage interface {
+	PutObject(ctx context.Context, key string, body io.Reader, opts *putObjectOptions) error
+	ListObjects(ctx context.Context, prefix string, startAfter *string, f func(storageObject) (bool, error)) (bool, error)
+	// ListPrefixes returns prefixes of the next path segment under the prefix ended with a slash, like `tenants/a/` of `tenants/`.
+	ListPrefixes(ctx context.Context, prefix string) ([]string, error)
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	DeleteObject(ctx context.Context, key string) error
+}
+
+type putObjectOptions struct {
+	ContentType     string
+	ContentEncoding string
+	SignalCount     int
+}
+
+type storageObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
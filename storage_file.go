@@ -0,0 +1,156 @@
+package oteleport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+type fileObjectStorage struct {
+	rootDir string
+}
+
+func newFileObjectStorage(cfg *StorageConfig) *fileObjectStorage {
+	return &fileObjectStorage{
+		rootDir: filepath.FromSlash(cfg.locationURL.Host + cfg.locationURL.Path),
+	}
+}
+
+func (s *fileObjectStorage) PutObject(ctx context.Context, key string, body io.Reader, _ *putObjectOptions) error {
+	p := filepath.Join(s.rootDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return oops.Wrapf(err, "failed to create directory")
+	}
+	// write to a temporary file and rename it, so that readers never see a partial object.
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return oops.Wrapf(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return oops.Wrapf(err, "failed to write file")
+	}
+	if err := tmp.Close(); err != nil {
+		return oops.Wrapf(err, "failed to close file")
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return oops.Wrapf(err, "failed to rename file")
+	}
+	slog.InfoContext(ctx, "put object", "path", p)
+	return nil
+}
+
+func (s *fileObjectStorage) ListObjects(ctx context.Context, prefix string, startAfter *string, f func(storageObject) (bool, error)) (bool, error) {
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+	objects := make([]storageObject, 0)
+	walkRoot := filepath.Join(s.rootDir, filepath.FromSlash(dir))
+	err := filepath.WalkDir(walkRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(s.rootDir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if d.IsDir() {
+			// directories never containing the prefix are not walked, like other partitions under the prefix directory.
+			if p != walkRoot && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if startAfter != nil && key <= *startAfter {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, storageObject{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return false, oops.Wrapf(err, "failed to list objects")
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	for _, obj := range objects {
+		ok, err := f(obj)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *fileObjectStorage) ListPrefixes(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.rootDir, filepath.FromSlash(prefix)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, oops.Wrapf(err, "failed to list prefixes")
+	}
+	prefixes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			prefixes = append(prefixes, prefix+entry.Name()+"/")
+		}
+	}
+	return prefixes, nil
+}
+
+func (s *fileObjectStorage) GetObject(_ context.Context, key string) ([]byte, error) {
+	body, err := os.ReadFile(filepath.Join(s.rootDir, filepath.FromSlash(key)))
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to get object")
+	}
+	return body, nil
+}
+
+func (s *fileObjectStorage) DeleteObject(ctx context.Context, key string) error {
+	p := filepath.Join(s.rootDir, filepath.FromSlash(key))
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return oops.Wrapf(err, "failed to delete object")
+	}
+	slog.InfoContext(ctx, "delete object", "path", p)
+	// remove emptied partition directories, stop at the first directory not empty.
+	root := filepath.Clean(s.rootDir) + string(filepath.Separator)
+	for dir := filepath.Dir(p); strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package oteleport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// memoryObjectStorage keeps objects in process memory as a ring buffer.
+// when the total number of signals or bytes exceeds the limit, the oldest objects are evicted.
+type memoryObjectStorage struct {
+	mu          sync.RWMutex
+	maxSignals  int64
+	maxBytes    int64
+	objects     map[string]*memoryObject
+	order       []string
+	signalCount int64
+	byteCount   int64
+}
+
+type memoryObject struct {
+	body         []byte
+	signalCount  int64
+	lastModified time.Time
+}
+
+func newMemoryObjectStorage(cfg *StorageConfig) *memoryObjectStorage {
+	return &memoryObjectStorage{
+		maxSignals: cfg.Memory.MaxSignals,
+		maxBytes:   cfg.Memory.MaxBytes,
+		objects:    make(map[string]*memoryObject),
+	}
+}
+
+func (s *memoryObjectStorage) PutObject(ctx context.Context, key string, body io.Reader, opts *putObjectOptions) error {
+	bs, err := io.ReadAll(body)
+	if err != nil {
+		return oops.Wrapf(err, "failed to read body")
+	}
+	obj := &memoryObject{
+		body:         bs,
+		lastModified: time.Now(),
+	}
+	if opts != nil {
+		obj.signalCount = int64(opts.SignalCount)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.objects[key]; ok {
+		s.signalCount -= old.signalCount
+		s.byteCount -= int64(len(old.body))
+		if i := slices.Index(s.order, key); i >= 0 {
+			s.order = slices.Delete(s.order, i, i+1)
+		}
+	}
+	s.objects[key] = obj
+	s.order = append(s.order, key)
+	s.signalCount += obj.signalCount
+	s.byteCount += int64(len(obj.body))
+	// always keep the newest object, even if it exceeds the limit by itself.
+	for len(s.order) > 1 && s.overLimit() {
+		oldest := s.order[0]
+		evicted := s.objects[oldest]
+		delete(s.objects, oldest)
+		s.order = s.order[1:]
+		s.signalCount -= evicted.signalCount
+		s.byteCount -= int64(len(evicted.body))
+		slog.DebugContext(ctx, "evict object", "key", oldest, "signals", evicted.signalCount, "bytes", len(evicted.body))
+	}
+	slog.InfoContext(ctx, "put object", "memory_key", key, "signals", obj.signalCount, "bytes", len(obj.body))
+	return nil
+}
+
+func (s *memoryObjectStorage) overLimit() bool {
+	if s.maxSignals > 0 && s.signalCount > s.maxSignals {
+		return true
+	}
+	if s.maxBytes > 0 && s.byteCount > s.maxBytes {
+		return true
+	}
+	return false
+}
+
+func (s *memoryObjectStorage) ListObjects(_ context.Context, prefix string, startAfter *string, f func(storageObject) (bool, error)) (bool, error) {
+	s.mu.RLock()
+	objects := make([]storageObject, 0)
+	for key, obj := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if startAfter != nil && key <= *startAfter {
+			continue
+		}
+		objects = append(objects, storageObject{
+			Key:          key,
+			Size:         int64(len(obj.body)),
+			LastModified: obj.lastModified,
+		})
+	}
+	s.mu.RUnlock()
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	for _, obj := range objects {
+		ok, err := f(obj)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *memoryObjectStorage) ListPrefixes(_ context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prefixes := make([]string, 0)
+	for key := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if i := strings.Index(key[len(prefix):], "/"); i >= 0 {
+			prefixes = append(prefixes, key[:len(prefix)+i+1])
+		}
+	}
+	slices.Sort(prefixes)
+	return slices.Compact(prefixes), nil
+}
+
+func (s *memoryObjectStorage) GetObject(_ context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[key]
+	if !ok {
+		return nil, oops.Errorf("object %q not found", key)
+	}
+	return bytes.Clone(obj.body), nil
+}
+
+func (s *memoryObjectStorage) DeleteObject(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[key]
+	if !ok {
+		return nil
+	}
+	delete(s.objects, key)
+	if i := slices.Index(s.order, key); i >= 0 {
+		s.order = slices.Delete(s.order, i, i+1)
+	}
+	s.signalCount -= obj.signalCount
+	s.byteCount -= int64(len(obj.body))
+	slog.InfoContext(ctx, "delete object", "memory_key", key)
+	return nil
+}
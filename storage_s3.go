@@ -0,0 +1,134 @@
+package oteleport
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/samber/oops"
+)
+
+type s3ObjectStorage struct {
+	bucketName string
+	client     *s3.Client
+	uploader   *manager.Uploader
+	downloader *manager.Downloader
+}
+
+func newS3ObjectStorage(cfg *StorageConfig) *s3ObjectStorage {
+	s3Opts := []func(*s3.Options){}
+	if cfg.AWS.Endpoint != "" {
+		s3Opts = append(s3Opts, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(cfg.AWS.Endpoint)
+		})
+	}
+	if cfg.AWS.UseS3PathStyle {
+		s3Opts = append(s3Opts, func(o *s3.Options) {
+			o.UsePathStyle = true
+		})
+	}
+	client := s3.NewFromConfig(cfg.AWS.awsConfig, s3Opts...)
+	return &s3ObjectStorage{
+		bucketName: cfg.locationURL.Host,
+		client:     client,
+		uploader:   manager.NewUploader(client),
+		downloader: manager.NewDownloader(client),
+	}
+}
+
+func (s *s3ObjectStorage) PutObject(ctx context.Context, key string, body io.Reader, opts *putObjectOptions) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if opts != nil {
+		if opts.ContentType != "" {
+			input.ContentType = aws.String(opts.ContentType)
+		}
+		if opts.ContentEncoding != "" {
+			input.ContentEncoding = aws.String(opts.ContentEncoding)
+		}
+	}
+	output, err := s.uploader.Upload(ctx, input)
+	if err != nil {
+		return oops.Wrapf(err, "failed to put object")
+	}
+	slog.InfoContext(ctx, "put object", "s3_url", output.Location, "etag", output.ETag, "version_id", output.VersionID)
+	return nil
+}
+
+func (s *s3ObjectStorage) ListObjects(ctx context.Context, prefix string, startAfter *string, f func(storageObject) (bool, error)) (bool, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket:     aws.String(s.bucketName),
+		Prefix:     aws.String(prefix),
+		StartAfter: startAfter,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, oops.Wrapf(err, "failed to list objects")
+		}
+		for _, obj := range page.Contents {
+			ok, err := f(storageObject{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func (s *s3ObjectStorage) ListPrefixes(ctx context.Context, prefix string) ([]string, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	prefixes := make([]string, 0)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, oops.Wrapf(err, "failed to list prefixes")
+		}
+		for _, p := range page.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(p.Prefix))
+		}
+	}
+	return prefixes, nil
+}
+
+func (s *s3ObjectStorage) GetObject(ctx context.Context, key string) ([]byte, error) {
+	var buf = make([]byte, 1024*1024*5) //5MB
+	w := manager.NewWriteAtBuffer(buf)
+	n, err := s.downloader.Download(ctx, w, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to get object")
+	}
+	return w.Bytes()[:n], nil
+}
+
+func (s *s3ObjectStorage) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return oops.Wrapf(err, "failed to delete object")
+	}
+	slog.InfoContext(ctx, "delete object", "s3_bucket", s.bucketName, "s3_key", key)
+	return nil
+}
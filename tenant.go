@@ -0,0 +1,105 @@
+package oteleport
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// tenantPattern is the allowed tenant name, the tenant name is a segment of object keys.
+var tenantPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// tenantKeyPrefix is the key prefix of tenants, signals of a tenant are stored under `tenants/<tenant>/`.
+// signals of access keys without a tenant are stored at the top level, and never under the tenants.
+const tenantKeyPrefix = "tenants"
+
+type accessKeyContextKey struct{}
+
+// contextWithAccessKey returns the context with the authenticated access key.
+func contextWithAccessKey(ctx context.Context, key *AccessKeyConfig) context.Context {
+	return context.WithValue(ctx, accessKeyContextKey{}, key)
+}
+
+// accessKeyFromContext returns the authenticated access key, or nil when auth is disabled.
+func accessKeyFromContext(ctx context.Context) *AccessKeyConfig {
+	key, _ := ctx.Value(accessKeyContextKey{}).(*AccessKeyConfig)
+	return key
+}
+
+// tenantRepository returns the repository of the tenant of the authenticated access key.
+func tenantRepository(ctx context.Context, repo SignalRepository) SignalRepository {
+	key := accessKeyFromContext(ctx)
+	if key == nil || key.Tenant == "" {
+		return repo
+	}
+	return repo.ForTenant(key.Tenant)
+}
+
+// ForTenant returns the repository of the tenant, which shares the storage and stores signals under the tenant prefix.
+// an empty tenant returns the repository of the top level.
+func (r *ObjectSignalRepository) ForTenant(tenant string) SignalRepository {
+	return r.tenantRepository(tenant)
+}
+
+func (r *ObjectSignalRepository) tenantRepository(tenant string) *ObjectSignalRepository {
+	if r.root != nil {
+		return r.root.tenantRepository(tenant)
+	}
+	if tenant == "" {
+		return r
+	}
+	r.tenantsMu.Lock()
+	defer r.tenantsMu.Unlock()
+	if t, ok := r.tenants[tenant]; ok {
+		return t
+	}
+	t := newObjectSignalRepository(r.cfg, r.storage, path.Join(r.objectPathPrefix, tenantKeyPrefix, tenant), r.bufferBudget)
+	t.root = r
+	t.tenant = tenant
+	r.tenants[tenant] = t
+	return t
+}
+
+// discoverTenants adds repositories of tenants found in the storage, like tenants only in bearer tokens,
+// which are not known until they send requests.
+func (r *ObjectSignalRepository) discoverTenants(ctx context.Context) error {
+	if r.root != nil {
+		return nil
+	}
+	tenantsPrefix := r.objectKeyPrefix(tenantKeyPrefix + "/")
+	prefixes, err := r.storage.ListPrefixes(ctx, tenantsPrefix)
+	if err != nil {
+		return oops.Wrapf(err, "failed to list tenants")
+	}
+	for _, prefix := range prefixes {
+		tenant := strings.TrimSuffix(strings.TrimPrefix(prefix, tenantsPrefix), "/")
+		if !tenantPattern.MatchString(tenant) {
+			slog.DebugContext(ctx, "skip invalid tenant prefix", "prefix", prefix)
+			continue
+		}
+		r.tenantRepository(tenant)
+	}
+	return nil
+}
+
+// allRepositories returns the repository of the top level and repositories of known tenants, in the tenant order.
+func (r *ObjectSignalRepository) allRepositories() []*ObjectSignalRepository {
+	r.tenantsMu.Lock()
+	defer r.tenantsMu.Unlock()
+	names := make([]string, 0, len(r.tenants))
+	for name := range r.tenants {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	repos := make([]*ObjectSignalRepository, 0, len(names)+1)
+	repos = append(repos, r)
+	for _, name := range names {
+		repos = append(repos, r.tenants[name])
+	}
+	return repos
+}
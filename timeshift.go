@@ -0,0 +1,147 @@
+package oteleport
+
+import (
+	"time"
+
+	"github.com/samber/oops"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// timeShifter rewrites timestamps of signals by a consistent offset, so that relative timing is preserved.
+// with shiftToNow, the offset is the time from the fetch start time to now,
+// so that the start of the time range is at the current time regardless of when the first signal is.
+type timeShifter struct {
+	offset time.Duration
+}
+
+// newTimeShifter returns the time shifter of the fetch from startTimeUnixNano, nil when timestamps are not shifted.
+// offsets shifting the start time before the unix epoch are rejected, timestamps can not be negative.
+func newTimeShifter(opts *ClientTimeShiftOptions, startTimeUnixNano int64) (*timeShifter, error) {
+	if !opts.ShiftToNow && opts.TimeOffset == 0 {
+		return nil, nil
+	}
+	s := &timeShifter{
+		offset: opts.TimeOffset,
+	}
+	if opts.ShiftToNow {
+		if startTimeUnixNano <= 0 {
+			return nil, oops.Errorf("shift to now needs the start time")
+		}
+		s.offset = time.Since(time.Unix(0, startTimeUnixNano))
+	}
+	if startTimeUnixNano+int64(s.offset) < 0 {
+		return nil, oops.Errorf("time offset %s shifts the start time before the unix epoch", s.offset)
+	}
+	return s, nil
+}
+
+// shift adds the offset to the timestamp, timestamps before the unix epoch are clamped at 0.
+func (s *timeShifter) shift(v *uint64) {
+	if *v == 0 {
+		return
+	}
+	if s.offset < 0 && *v <= uint64(-s.offset) {
+		*v = 0
+		return
+	}
+	*v = uint64(int64(*v) + int64(s.offset))
+}
+
+func (s *timeShifter) ShiftResourceSpans(resourceSpans []*tracepb.ResourceSpans) {
+	if s == nil {
+		return
+	}
+	for _, rs := range resourceSpans {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				s.shift(&span.StartTimeUnixNano)
+				s.shift(&span.EndTimeUnixNano)
+				for _, event := range span.GetEvents() {
+					s.shift(&event.TimeUnixNano)
+				}
+			}
+		}
+	}
+}
+
+func (s *timeShifter) ShiftResourceMetrics(resourceMetrics []*metricspb.ResourceMetrics) {
+	if s == nil {
+		return
+	}
+	walkDataPointTimes(resourceMetrics, func(startTimeUnixNano *uint64, timeUnixNano *uint64) {
+		s.shift(startTimeUnixNano)
+		s.shift(timeUnixNano)
+	}, func(exemplar *metricspb.Exemplar) {
+		s.shift(&exemplar.TimeUnixNano)
+	})
+}
+
+func (s *timeShifter) ShiftResourceLogs(resourceLogs []*logspb.ResourceLogs) {
+	if s == nil {
+		return
+	}
+	for _, rl := range resourceLogs {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, logRecord := range sl.GetLogRecords() {
+				s.shift(&logRecord.TimeUnixNano)
+				s.shift(&logRecord.ObservedTimeUnixNano)
+			}
+		}
+	}
+}
+
+// earlier returns the earlier non-zero timestamp.
+func earlier(a, b uint64) uint64 {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	return min(a, b)
+}
+
+func walkDataPointTimes(resourceMetrics []*metricspb.ResourceMetrics, f func(startTimeUnixNano *uint64, timeUnixNano *uint64), exemplarFunc func(*metricspb.Exemplar)) {
+	exemplars := func(es []*metricspb.Exemplar) {
+		if exemplarFunc == nil {
+			return
+		}
+		for _, e := range es {
+			exemplarFunc(e)
+		}
+	}
+	for _, rm := range resourceMetrics {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, metric := range sm.GetMetrics() {
+				switch data := metric.GetData().(type) {
+				case *metricspb.Metric_Gauge:
+					for _, dp := range data.Gauge.GetDataPoints() {
+						f(&dp.StartTimeUnixNano, &dp.TimeUnixNano)
+						exemplars(dp.GetExemplars())
+					}
+				case *metricspb.Metric_Sum:
+					for _, dp := range data.Sum.GetDataPoints() {
+						f(&dp.StartTimeUnixNano, &dp.TimeUnixNano)
+						exemplars(dp.GetExemplars())
+					}
+				case *metricspb.Metric_Histogram:
+					for _, dp := range data.Histogram.GetDataPoints() {
+						f(&dp.StartTimeUnixNano, &dp.TimeUnixNano)
+						exemplars(dp.GetExemplars())
+					}
+				case *metricspb.Metric_ExponentialHistogram:
+					for _, dp := range data.ExponentialHistogram.GetDataPoints() {
+						f(&dp.StartTimeUnixNano, &dp.TimeUnixNano)
+						exemplars(dp.GetExemplars())
+					}
+				case *metricspb.Metric_Summary:
+					for _, dp := range data.Summary.GetDataPoints() {
+						f(&dp.StartTimeUnixNano, &dp.TimeUnixNano)
+					}
+				}
+			}
+		}
+	}
+}
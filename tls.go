@@ -0,0 +1,204 @@
+package oteleport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/oops"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// tlsReloadInterval is the minimum interval of checking the certificate files.
+const tlsReloadInterval = time.Second
+
+// tlsReloader holds the certificate and the client CA of a listener, and loads them again when the files are changed.
+// files are checked at handshakes at most once per tlsReloadInterval, so that renewed certificates are used without restarting.
+type tlsReloader struct {
+	cfg        *TLSConfig
+	nextProtos []string
+	lastCheck  atomic.Int64
+
+	mu             sync.Mutex
+	cert           *tls.Certificate
+	clientCAs      *x509.CertPool
+	modTimes       map[string]time.Time
+	failedModTimes map[string]time.Time
+}
+
+// newServerTLSConfig returns the TLS config of the listener, nextProtos is the ALPN of the server like `h2`.
+func newServerTLSConfig(cfg *TLSConfig, nextProtos []string) (*tls.Config, error) {
+	r := &tlsReloader{
+		cfg:        cfg,
+		nextProtos: nextProtos,
+		modTimes:   make(map[string]time.Time),
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:         cfg.minVersion,
+		NextProtos:         nextProtos,
+		GetConfigForClient: r.getConfigForClient,
+	}, nil
+}
+
+func (r *tlsReloader) getConfigForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	check := r.checkDue()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if check {
+		if changed, err := r.reload(); err != nil {
+			// the loaded certificate is used until the files are fixed, like while the files are being written.
+			slog.WarnContext(hello.Context(), "failed to reload tls certificate, use the loaded certificate", "cert_file", r.cfg.CertFile, "error", err.Error())
+		} else if changed {
+			slog.InfoContext(hello.Context(), "reloaded tls certificate", "cert_file", r.cfg.CertFile)
+		}
+	}
+	return &tls.Config{
+		MinVersion:   r.cfg.minVersion,
+		NextProtos:   r.nextProtos,
+		Certificates: []tls.Certificate{*r.cert},
+		ClientAuth:   r.cfg.clientAuth,
+		ClientCAs:    r.clientCAs,
+	}, nil
+}
+
+// checkDue reports whether the files are checked at this handshake, only one handshake in tlsReloadInterval checks them.
+func (r *tlsReloader) checkDue() bool {
+	now := time.Now().UnixNano()
+	last := r.lastCheck.Load()
+	return now-last >= int64(tlsReloadInterval) && r.lastCheck.CompareAndSwap(last, now)
+}
+
+// reload loads the files if any of them is changed from the last load, and reports whether they are loaded.
+// a failure is reported once, the files are not loaded again until any of them is changed from the failed load.
+func (r *tlsReloader) reload() (bool, error) {
+	modTimes, err := r.statFiles()
+	if err == nil && maps.EqualFunc(modTimes, r.modTimes, time.Time.Equal) {
+		return false, nil
+	}
+	if r.failedModTimes != nil && maps.EqualFunc(modTimes, r.failedModTimes, time.Time.Equal) {
+		return false, nil
+	}
+	if err == nil {
+		err = r.load()
+	}
+	if err != nil {
+		r.failedModTimes = modTimes
+		return false, err
+	}
+	r.modTimes = modTimes
+	r.failedModTimes = nil
+	return true, nil
+}
+
+// statFiles returns the modification times of the files, the time of a file failed to stat is zero.
+func (r *tlsReloader) statFiles() (map[string]time.Time, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	modTimes := make(map[string]time.Time, len(files))
+	var statErr error
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			modTimes[file] = time.Time{}
+			if statErr == nil {
+				statErr = oops.Wrapf(err, "failed to stat %s", file)
+			}
+			continue
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, statErr
+}
+
+// load loads the certificate and the client CA from the files.
+func (r *tlsReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return oops.Wrapf(err, "failed to load certificate")
+	}
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return oops.Wrapf(err, "failed to read client ca")
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return oops.Errorf("no certificates in client ca %s", r.cfg.ClientCAFile)
+		}
+	}
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	return nil
+}
+
+// serverCredentials returns the gRPC server credentials of the TLS config.
+func serverCredentials(cfg *TLSConfig) (credentials.TransportCredentials, error) {
+	tlsCfg, err := newServerTLSConfig(cfg, []string{"h2"})
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// newTLSListener wraps the listener of HTTP servers with TLS, the listener is returned as is when TLS is not enabled.
+func newTLSListener(l net.Listener, cfg *TLSConfig) (net.Listener, error) {
+	if !cfg.Enabled() {
+		return l, nil
+	}
+	tlsCfg, err := newServerTLSConfig(cfg, []string{"h2", "http/1.1"})
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(l, tlsCfg), nil
+}
+
+type clientCertificateContextKey struct{}
+
+// withClientCertificate puts the verified client certificate of the HTTP request into the request context.
+func withClientCertificate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			r = r.WithContext(context.WithValue(r.Context(), clientCertificateContextKey{}, r.TLS.VerifiedChains[0][0]))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientCertificateFromContext returns the verified client certificate of HTTP and gRPC requests.
+func clientCertificateFromContext(ctx context.Context) *x509.Certificate {
+	if cert, ok := ctx.Value(clientCertificateContextKey{}).(*x509.Certificate); ok {
+		return cert
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
+
+// matchClientCertificate reports whether the certificate is of the client cert subject, by the common name or the distinguished name.
+func (c *AccessKeyConfig) matchClientCertificate(cert *x509.Certificate) bool {
+	if c.ClientCertSubject == "" {
+		return false
+	}
+	return c.ClientCertSubject == cert.Subject.CommonName || c.ClientCertSubject == cert.Subject.String()
+}
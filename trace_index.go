@@ -0,0 +1,172 @@
+package oteleport
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// traceIndexEntry is a line of the trace id index object.
+// index objects are written alongside span objects, one line per trace id in the span object.
+type traceIndexEntry struct {
+	TraceID         string `json:"trace_id"`
+	ObjectKey       string `json:"object_key"`
+	MinTimeUnixNano uint64 `json:"min_time_unix_nano"`
+	MaxTimeUnixNano uint64 `json:"max_time_unix_nano"`
+}
+
+func newTraceIndexEntries(objectKey string, resourceSpans []*tracepb.ResourceSpans) []*traceIndexEntry {
+	entries := make([]*traceIndexEntry, 0)
+	entryByTraceID := make(map[string]*traceIndexEntry)
+	for _, rs := range resourceSpans {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				traceID := hex.EncodeToString(span.GetTraceId())
+				startTime := span.GetStartTimeUnixNano()
+				endTime := max(span.GetEndTimeUnixNano(), startTime)
+				entry, ok := entryByTraceID[traceID]
+				if !ok {
+					entry = &traceIndexEntry{
+						TraceID:         traceID,
+						ObjectKey:       objectKey,
+						MinTimeUnixNano: startTime,
+						MaxTimeUnixNano: endTime,
+					}
+					entryByTraceID[traceID] = entry
+					entries = append(entries, entry)
+					continue
+				}
+				entry.MinTimeUnixNano = min(entry.MinTimeUnixNano, startTime)
+				entry.MaxTimeUnixNano = max(entry.MaxTimeUnixNano, endTime)
+			}
+		}
+	}
+	return entries
+}
+
+func (r *ObjectSignalRepository) tracesIndexObjectKeyPrefixes(t time.Time) []string {
+	return r.objectKeyPrefixes("traces-index", "")(t)
+}
+
+func (r *ObjectSignalRepository) putTraceIndex(ctx context.Context, objectKeySuffix string, entries []*traceIndexEntry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return oops.Wrapf(err, "failed to encode trace index entry")
+		}
+	}
+	if _, err := r.putObject(ctx, objectKeySuffix, &buf, 0); err != nil {
+		return oops.Wrapf(err, "failed to put trace index object")
+	}
+	return nil
+}
+
+// traceIndexLookup decides which span objects may contain the given trace ids.
+// index objects are loaded lazily, once per partition.
+type traceIndexLookup struct {
+	r          *ObjectSignalRepository
+	traceIDs   map[string]bool
+	startTime  time.Time
+	endTime    time.Time
+	partitions map[string]*traceIndexPartition
+}
+
+type traceIndexPartition struct {
+	indexed map[string]bool
+	matched map[string]bool
+}
+
+// newTraceIndexLookup returns nil when traceIDs is empty, nil lookup means all objects may contain the traces.
+// when startTime and endTime are zero, index entries are not checked by time.
+func (r *ObjectSignalRepository) newTraceIndexLookup(traceIDs []string, startTime time.Time, endTime time.Time) (*traceIndexLookup, error) {
+	if len(traceIDs) == 0 {
+		return nil, nil
+	}
+	l := &traceIndexLookup{
+		r:          r,
+		traceIDs:   make(map[string]bool, len(traceIDs)),
+		startTime:  startTime,
+		endTime:    endTime,
+		partitions: make(map[string]*traceIndexPartition),
+	}
+	for _, traceID := range traceIDs {
+		bs, err := hex.DecodeString(traceID)
+		if err != nil || len(bs) == 0 {
+			return nil, oops.Errorf("invalid trace id %q", traceID)
+		}
+		l.traceIDs[hex.EncodeToString(bs)] = true
+	}
+	return l, nil
+}
+
+// mayContain reports whether the span object in the partition of t may contain the traces.
+// objects not found in the index, like written by older versions, are always reported as may contain.
+func (l *traceIndexLookup) mayContain(ctx context.Context, t time.Time, objectKey string) (bool, error) {
+	if l == nil {
+		return true, nil
+	}
+	for _, prefix := range l.r.tracesIndexObjectKeyPrefixes(t) {
+		p, ok := l.partitions[prefix]
+		if !ok {
+			var err error
+			p, err = l.load(ctx, prefix)
+			if err != nil {
+				return false, err
+			}
+			l.partitions[prefix] = p
+		}
+		if p.indexed[objectKey] {
+			return p.matched[objectKey], nil
+		}
+	}
+	return true, nil
+}
+
+func (l *traceIndexLookup) load(ctx context.Context, prefix string) (*traceIndexPartition, error) {
+	p := &traceIndexPartition{
+		indexed: make(map[string]bool),
+		matched: make(map[string]bool),
+	}
+	_, err := l.r.storage.ListObjects(ctx, prefix, nil, func(obj storageObject) (bool, error) {
+		body, err := l.r.getObjectBody(ctx, obj)
+		if err != nil {
+			return false, oops.Wrapf(err, "failed to get trace index object %q", obj.Key)
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		for dec.More() {
+			var entry traceIndexEntry
+			if err := dec.Decode(&entry); err != nil {
+				return false, oops.Wrapf(err, "failed to decode trace index object %q", obj.Key)
+			}
+			p.indexed[entry.ObjectKey] = true
+			if l.match(&entry) {
+				p.matched[entry.ObjectKey] = true
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (l *traceIndexLookup) match(entry *traceIndexEntry) bool {
+	if !l.traceIDs[strings.ToLower(entry.TraceID)] {
+		return false
+	}
+	if !l.startTime.IsZero() && entry.MaxTimeUnixNano < uint64(l.startTime.UnixNano()) {
+		return false
+	}
+	if !l.endTime.IsZero() && entry.MinTimeUnixNano > uint64(l.endTime.UnixNano()) {
+		return false
+	}
+	return true
+}